@@ -0,0 +1,186 @@
+package budget
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/filter"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/stats"
+)
+
+const (
+	UnitBytes    = "bytes"
+	UnitRequests = "requests"
+	UnitMillis   = "ms"
+	UnitShare    = "share"
+
+	anyHost = "*"
+)
+
+type Result struct {
+	Budget string  `json:"budget"`
+	Limit  float64 `json:"limit"`
+	Actual float64 `json:"actual"`
+	Unit   string  `json:"unit"`
+	Detail string  `json:"detail,omitempty"`
+	Passed bool    `json:"passed"`
+}
+
+func (r Result) String() string {
+	status := "pass"
+	if !r.Passed {
+		status = "fail"
+	}
+
+	s := fmt.Sprintf("%s: %s: %s (limit %s)", status, r.Budget, formatValue(r.Actual, r.Unit), formatValue(r.Limit, r.Unit))
+	if r.Detail != "" {
+		s += ": " + r.Detail
+	}
+	return s
+}
+
+func formatValue(value float64, unit string) string {
+	if unit == UnitShare {
+		return fmt.Sprintf("%.1f%%", value*100)
+	}
+	return fmt.Sprintf("%.0f %s", value, unit)
+}
+
+func Failed(results []Result) []Result {
+	failed := make([]Result, 0)
+	for _, result := range results {
+		if !result.Passed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+func Evaluate(h *har.HAR, config Config) []Result {
+	results := make([]Result, 0)
+	add := func(budget string, limit, actual float64, unit, detail string) {
+		results = append(results, Result{
+			Budget: budget,
+			Limit:  limit,
+			Actual: actual,
+			Unit:   unit,
+			Detail: detail,
+			Passed: actual <= limit,
+		})
+	}
+
+	if config.Total != nil {
+		size, count := totals(h.Log.Entries, func(har.Entry) bool { return true })
+		addLimit(add, "total", *config.Total, size, count)
+	}
+
+	resourceTypes := make([]string, 0, len(config.ResourceTypes))
+	for resourceType := range config.ResourceTypes {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+	for _, resourceType := range resourceTypes {
+		size, count := totals(h.Log.Entries, func(entry har.Entry) bool {
+			return strings.EqualFold(entry.Custom.String(chromedriver2har.CustomResourceType), resourceType)
+		})
+		addLimit(add, "resourceTypes."+resourceType, config.ResourceTypes[resourceType], size, count)
+	}
+
+	if config.MaxRequestTime != nil {
+		slowest, url := 0.0, ""
+		for _, entry := range h.Log.Entries {
+			if entry.Time > slowest {
+				slowest, url = entry.Time, entry.Request.URL.String()
+			}
+		}
+		add("maxRequestTime", config.MaxRequestTime.milliseconds(), slowest, UnitMillis, url)
+	}
+
+	if config.MaxThirdPartyShare != nil && len(h.Log.Entries) > 0 {
+		_, thirdParty := totals(h.Log.Entries, filter.ThirdParty)
+		add("maxThirdPartyShare", *config.MaxThirdPartyShare, thirdParty/float64(len(h.Log.Entries)), UnitShare, "")
+	}
+
+	if config.MaxOnLoad != nil {
+		for _, page := range h.Log.Pages {
+			if page.PageTimings.OnLoad != nil {
+				add("maxOnLoad", config.MaxOnLoad.milliseconds(), *page.PageTimings.OnLoad, UnitMillis, page.ID)
+			}
+		}
+	}
+
+	for _, host := range ttfbHosts(h.Log.Entries, config.MaxTTFB) {
+		limit, ok := config.MaxTTFB[host.name]
+		if !ok {
+			limit = config.MaxTTFB[anyHost]
+		}
+		add("maxTTFB."+host.name, limit.milliseconds(), host.wait, UnitMillis, host.url)
+	}
+
+	return results
+}
+
+func addLimit(add func(string, float64, float64, string, string), budget string, limit Limit, size, count float64) {
+	if limit.Size != nil {
+		add(budget+".size", float64(*limit.Size), size, UnitBytes, "")
+	}
+	if limit.Count != nil {
+		add(budget+".count", float64(*limit.Count), count, UnitRequests, "")
+	}
+}
+
+func totals(entries []har.Entry, match func(har.Entry) bool) (size, count float64) {
+	for _, entry := range entries {
+		if match(entry) {
+			size += float64(stats.TransferSize(entry))
+			count++
+		}
+	}
+	return size, count
+}
+
+type hostTTFB struct {
+	name string
+	wait float64
+	url  string
+}
+
+func ttfbHosts(entries []har.Entry, limits map[string]Duration) []hostTTFB {
+	if len(limits) == 0 {
+		return nil
+	}
+
+	hosts := make(map[string]*hostTTFB)
+	for _, entry := range entries {
+		name := entry.Request.URL.Hostname()
+		if name == "" {
+			continue
+		}
+		if _, ok := limits[name]; !ok {
+			if _, ok := limits[anyHost]; !ok {
+				continue
+			}
+		}
+
+		host, ok := hosts[name]
+		if !ok {
+			host = &hostTTFB{name: name}
+			hosts[name] = host
+		}
+		if host.url == "" || entry.Timings.Wait > host.wait {
+			host.wait, host.url = entry.Timings.Wait, entry.Request.URL.String()
+		}
+	}
+
+	sorted := make([]hostTTFB, 0, len(hosts))
+	for _, host := range hosts {
+		sorted = append(sorted, *host)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].name < sorted[j].name
+	})
+	return sorted
+}
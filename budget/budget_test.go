@@ -0,0 +1,112 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/internal/hartest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	yamlConfig, err := Parse([]byte(`
+total:
+  count: 50
+resourceTypes:
+  script:
+    size: 500KB
+maxRequestTime: 2s
+maxThirdPartyShare: 0.25
+maxTTFB:
+  "*": 300
+`))
+	require.NoError(t, err)
+
+	jsonConfig, err := Parse([]byte(`{
+		"total": {"count": 50},
+		"resourceTypes": {"script": {"size": 512000}},
+		"maxRequestTime": 2000,
+		"maxThirdPartyShare": 0.25,
+		"maxTTFB": {"*": "300ms"}
+	}`))
+	require.NoError(t, err)
+
+	require.Equal(t, yamlConfig, jsonConfig)
+	require.Equal(t, Size(500*1024), *yamlConfig.ResourceTypes["script"].Size)
+	require.Nil(t, yamlConfig.ResourceTypes["script"].Count)
+
+	zeroConfig, err := Parse([]byte(`resourceTypes: {font: {count: 0}}`))
+	require.NoError(t, err)
+	require.Equal(t, 0, *zeroConfig.ResourceTypes["font"].Count)
+	require.Equal(t, Duration(2*time.Second), *yamlConfig.MaxRequestTime)
+	require.Nil(t, yamlConfig.MaxOnLoad)
+
+	zeroConfig, err = Parse([]byte(`{"maxRequestTime": 0, "maxOnLoad": "0s"}`))
+	require.NoError(t, err)
+	require.Equal(t, Duration(0), *zeroConfig.MaxRequestTime)
+	require.Equal(t, Duration(0), *zeroConfig.MaxOnLoad)
+
+	_, err = Parse([]byte(`maxRequestTime: soon`))
+	require.Error(t, err)
+
+	_, err = Parse([]byte(`maxRequstTime: 2s`))
+	require.Error(t, err)
+}
+
+func TestEvaluate(t *testing.T) {
+	entry := func(rawURL string) *hartest.Entry {
+		return hartest.NewEntry("GET", rawURL).Custom(chromedriver2har.CustomDocumentURL, "https://example.com/")
+	}
+	resourceType := chromedriver2har.CustomResourceType
+
+	onLoad := 2500.0
+	h := &har.HAR{Log: har.Log{
+		Pages: []har.Page{{ID: "page_1", PageTimings: har.PageTimings{OnLoad: &onLoad}}},
+		Entries: []har.Entry{
+			entry("https://example.com/").Custom(resourceType, "Document").BodySize(20000).Time(160).Timings(har.Timings{Wait: 150}).Build(),
+			entry("https://example.com/app.js").Custom(resourceType, "Script").BodySize(300000).Time(100).Timings(har.Timings{Wait: 90}).Build(),
+			entry("https://cdn.example.net/vendor.js").Custom(resourceType, "Script").BodySize(250000).Time(410).Timings(har.Timings{Wait: 400}).Build(),
+			entry("https://api.example.com/data").Custom(resourceType, "XHR").BodySize(-1).ContentSize(4000).Time(1910).Timings(har.Timings{Wait: 1900}).Build(),
+		},
+	}}
+
+	share := 0.2
+	totalCount, scriptSize, scriptCount, fontCount := 10, Size(500*1024), 5, 0
+	maxRequestTime, maxOnLoad := Duration(2*time.Second), Duration(3*time.Second)
+	config := Config{
+		Total:              &Limit{Count: &totalCount},
+		ResourceTypes:      map[string]Limit{"font": {Count: &fontCount}, "script": {Size: &scriptSize, Count: &scriptCount}},
+		MaxRequestTime:     &maxRequestTime,
+		MaxThirdPartyShare: &share,
+		MaxOnLoad:          &maxOnLoad,
+		MaxTTFB:            map[string]Duration{"*": Duration(time.Second), "api.example.com": Duration(500 * time.Millisecond)},
+	}
+
+	results := Evaluate(h, config)
+	require.Equal(t, []Result{
+		{Budget: "total.count", Limit: 10, Actual: 4, Unit: UnitRequests, Passed: true},
+		{Budget: "resourceTypes.font.count", Limit: 0, Actual: 0, Unit: UnitRequests, Passed: true},
+		{Budget: "resourceTypes.script.size", Limit: 512000, Actual: 550000, Unit: UnitBytes},
+		{Budget: "resourceTypes.script.count", Limit: 5, Actual: 2, Unit: UnitRequests, Passed: true},
+		{Budget: "maxRequestTime", Limit: 2000, Actual: 1910, Unit: UnitMillis, Detail: "https://api.example.com/data", Passed: true},
+		{Budget: "maxThirdPartyShare", Limit: 0.2, Actual: 0.25, Unit: UnitShare},
+		{Budget: "maxOnLoad", Limit: 3000, Actual: 2500, Unit: UnitMillis, Detail: "page_1", Passed: true},
+		{Budget: "maxTTFB.api.example.com", Limit: 500, Actual: 1900, Unit: UnitMillis, Detail: "https://api.example.com/data"},
+		{Budget: "maxTTFB.cdn.example.net", Limit: 1000, Actual: 400, Unit: UnitMillis, Detail: "https://cdn.example.net/vendor.js", Passed: true},
+		{Budget: "maxTTFB.example.com", Limit: 1000, Actual: 150, Unit: UnitMillis, Detail: "https://example.com/", Passed: true},
+	}, results)
+
+	require.Len(t, Failed(results), 3)
+	require.Equal(t, "fail: maxThirdPartyShare: 25.0% (limit 20.0%)", Failed(results)[1].String())
+
+	zero := Duration(0)
+	require.Equal(t, []Result{
+		{Budget: "maxRequestTime", Limit: 0, Actual: 1910, Unit: UnitMillis, Detail: "https://api.example.com/data"},
+		{Budget: "maxOnLoad", Limit: 0, Actual: 2500, Unit: UnitMillis, Detail: "page_1"},
+	}, Evaluate(h, Config{MaxRequestTime: &zero, MaxOnLoad: &zero}))
+
+	totalSize := Size(1 << 20)
+	require.True(t, Assert(t, h, Config{Total: &Limit{Size: &totalSize}}))
+}
@@ -0,0 +1,156 @@
+package budget
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+var sizeUnits = []struct {
+	suffix string
+	bytes  float64
+}{
+	{"kib", 1 << 10},
+	{"mib", 1 << 20},
+	{"kb", 1 << 10},
+	{"mb", 1 << 20},
+	{"k", 1 << 10},
+	{"m", 1 << 20},
+	{"b", 1},
+}
+
+type Config struct {
+	Total              *Limit              `json:"total,omitempty" yaml:"total,omitempty"`
+	ResourceTypes      map[string]Limit    `json:"resourceTypes,omitempty" yaml:"resourceTypes,omitempty"`
+	MaxRequestTime     *Duration           `json:"maxRequestTime,omitempty" yaml:"maxRequestTime,omitempty"`
+	MaxThirdPartyShare *float64            `json:"maxThirdPartyShare,omitempty" yaml:"maxThirdPartyShare,omitempty"`
+	MaxOnLoad          *Duration           `json:"maxOnLoad,omitempty" yaml:"maxOnLoad,omitempty"`
+	MaxTTFB            map[string]Duration `json:"maxTTFB,omitempty" yaml:"maxTTFB,omitempty"`
+}
+
+// Limit caps the transfer size and request count of a group of entries.
+// Unset fields aren't checked; a zero limit forbids any.
+type Limit struct {
+	Size  *Size `json:"size,omitempty" yaml:"size,omitempty"`
+	Count *int  `json:"count,omitempty" yaml:"count,omitempty"`
+}
+
+// Size is a byte count that can also be written as a string such as "500KB".
+// KB and MB are binary multiples, as in Chrome DevTools.
+type Size int64
+
+func (s *Size) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return s.set(v)
+}
+
+func (s *Size) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v interface{}
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	return s.set(v)
+}
+
+func (s *Size) set(v interface{}) error {
+	switch v := v.(type) {
+	case float64:
+		*s = Size(v)
+	case int:
+		*s = Size(v)
+	case string:
+		size, err := parseSize(v)
+		if err != nil {
+			return err
+		}
+		*s = size
+	default:
+		return errors.Errorf("invalid size %v", v)
+	}
+	return nil
+}
+
+func parseSize(s string) (Size, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(s))
+	multiplier := 1.0
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(trimmed, unit.suffix) {
+			trimmed = strings.TrimSpace(strings.TrimSuffix(trimmed, unit.suffix))
+			multiplier = unit.bytes
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid size %q", s)
+	}
+	return Size(value * multiplier), nil
+}
+
+// Duration is a time.Duration that can be written as a string such as "2s" or
+// as a number of milliseconds.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return d.set(v)
+}
+
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v interface{}
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	return d.set(v)
+}
+
+func (d *Duration) set(v interface{}) error {
+	switch v := v.(type) {
+	case float64:
+		*d = Duration(v * float64(time.Millisecond))
+	case int:
+		*d = Duration(time.Duration(v) * time.Millisecond)
+	case string:
+		duration, err := time.ParseDuration(v)
+		if err != nil {
+			return errors.Wrapf(err, "invalid duration %q", v)
+		}
+		*d = Duration(duration)
+	default:
+		return errors.Errorf("invalid duration %v", v)
+	}
+	return nil
+}
+
+func (d Duration) milliseconds() float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Parse reads a YAML or JSON config, JSON being a subset of YAML.
+func Parse(data []byte) (Config, error) {
+	var config Config
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return Config{}, errors.Wrap(err, "failed to unmarshal budget config")
+	}
+	return config, nil
+}
+
+func Load(path string) (Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Config{}, errors.Wrapf(err, "failed to read %q", path)
+	}
+	return Parse(data)
+}
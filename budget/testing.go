@@ -0,0 +1,19 @@
+package budget
+
+import (
+	"testing"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+)
+
+// Assert reports every failed budget as a test error and returns whether all
+// budgets passed.
+func Assert(t testing.TB, h *har.HAR, config Config) bool {
+	t.Helper()
+
+	failed := Failed(Evaluate(h, config))
+	for _, result := range failed {
+		t.Errorf("budget exceeded: %s", result)
+	}
+	return len(failed) == 0
+}
@@ -0,0 +1,172 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/pkg/errors"
+)
+
+const (
+	methodNetworkEnable          = "Network.enable"
+	methodNetworkGetResponseBody = "Network.getResponseBody"
+	methodNetworkLoadingFinished = "Network.loadingFinished"
+	methodPageEnable             = "Page.enable"
+	methodPageNavigate           = "Page.navigate"
+
+	responseBodyTimeout = 10 * time.Second
+)
+
+type Capture struct {
+	client   *client
+	targetID string
+
+	mu      sync.Mutex
+	events  []chromedriver2har.Event
+	bodies  map[string]chromedriver2har.NetworkGetResponseBody
+	stopped bool
+
+	// fetches tracks in-flight Network.getResponseBody calls. They can't be
+	// made from onEvent, which runs on the client's read loop.
+	fetches sync.WaitGroup
+
+	stopOnce sync.Once
+	har      *har.HAR
+	stopErr  error
+}
+
+func Dial(ctx context.Context, debuggingAddr string) (*Capture, error) {
+	if strings.HasPrefix(debuggingAddr, "ws://") || strings.HasPrefix(debuggingAddr, "wss://") {
+		return DialTarget(ctx, Target{WebSocketDebuggerURL: debuggingAddr})
+	}
+
+	target, err := firstPageTarget(ctx, debuggingAddr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find page target")
+	}
+	return DialTarget(ctx, target)
+}
+
+func DialTarget(ctx context.Context, target Target) (*Capture, error) {
+	c := &Capture{
+		targetID: target.ID,
+		bodies:   make(map[string]chromedriver2har.NetworkGetResponseBody),
+	}
+
+	client, err := dial(ctx, target.WebSocketDebuggerURL, c.onEvent)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.client = client
+	c.mu.Unlock()
+
+	for _, method := range []string{methodNetworkEnable, methodPageEnable} {
+		if _, err := client.call(ctx, method, nil); err != nil {
+			client.close()
+			return nil, errors.Wrapf(err, "failed to call %q", method)
+		}
+	}
+
+	return c, nil
+}
+
+func (c *Capture) onEvent(method string, params json.RawMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.events = append(c.events, chromedriver2har.Event{
+		Method:    method,
+		Params:    params,
+		Timestamp: time.Now(),
+		TargetID:  c.targetID,
+	})
+
+	if method == methodNetworkLoadingFinished && !c.stopped {
+		var loadingFinished chromedriver2har.NetworkLoadingFinished
+		if err := json.Unmarshal(params, &loadingFinished); err == nil {
+			c.fetches.Add(1)
+			go c.fetchResponseBody(c.client, loadingFinished.RequestID)
+		}
+	}
+}
+
+func (c *Capture) fetchResponseBody(client *client, requestID string) {
+	defer c.fetches.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), responseBodyTimeout)
+	defer cancel()
+
+	// Redirects, 204s and evicted resources have no body; leave them out.
+	result, err := client.call(ctx, methodNetworkGetResponseBody, map[string]string{"requestId": requestID})
+	if err != nil {
+		return
+	}
+
+	var body chromedriver2har.NetworkGetResponseBody
+	if err := json.Unmarshal(result, &body); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bodies[requestID] = body
+}
+
+// ResponseBody implements chromedriver2har.BodyProvider with the bodies
+// fetched as each request finished loading.
+func (c *Capture) ResponseBody(requestID string) (chromedriver2har.NetworkGetResponseBody, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	body, ok := c.bodies[requestID]
+	return body, ok
+}
+
+func (c *Capture) Call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	return c.client.call(ctx, method, params)
+}
+
+func (c *Capture) Navigate(ctx context.Context, url string) error {
+	_, err := c.client.call(ctx, methodPageNavigate, map[string]string{"url": url})
+	return errors.Wrapf(err, "failed to navigate to %q", url)
+}
+
+func (c *Capture) Events() []chromedriver2har.Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	events := make([]chromedriver2har.Event, len(c.events))
+	copy(events, c.events)
+	return events
+}
+
+// Stop closes the connection and builds a HAR from the recorded events. If
+// closing failed, the HAR is returned together with the error. Later calls
+// return the same result.
+func (c *Capture) Stop() (*har.HAR, error) {
+	c.stopOnce.Do(func() {
+		c.har, c.stopErr = c.finish()
+	})
+	return c.har, c.stopErr
+}
+
+func (c *Capture) finish() (*har.HAR, error) {
+	c.mu.Lock()
+	c.stopped = true
+	c.mu.Unlock()
+	c.fetches.Wait()
+
+	closeErr := c.client.close()
+
+	h, err := chromedriver2har.FromEventSource(chromedriver2har.NewSliceSource(c.Events()), chromedriver2har.WithBodyProvider(c))
+	if err != nil {
+		return nil, err
+	}
+	return h, errors.Wrap(closeErr, "failed to close connection")
+}
@@ -0,0 +1,93 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var events = []string{
+	`{"method":"Network.requestWillBeSent","params":{"requestId":"1.1","loaderId":"1.1","documentURL":"https://example.com/","request":{"url":"https://example.com/","method":"GET","headers":{}},"timestamp":100.0,"wallTime":1760695200.123}}`,
+	`{"method":"Network.responseReceived","params":{"requestId":"1.1","loaderId":"1.1","timestamp":100.05,"type":"Document","response":{"url":"https://example.com/","status":200,"statusText":"OK","headers":{"Content-Type":"text/html"},"mimeType":"text/html","protocol":"http/1.1"}}}`,
+	`{"method":"Network.dataReceived","params":{"requestId":"1.1","timestamp":100.06,"dataLength":512,"encodedDataLength":512}}`,
+	`{"method":"Network.loadingFinished","params":{"requestId":"1.1","timestamp":100.07,"encodedDataLength":600}}`,
+	`{"method":"Page.loadEventFired","params":{"timestamp":100.2}}`,
+}
+
+func fakeBrowser(t *testing.T) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	mux.HandleFunc("/json/list", func(w http.ResponseWriter, r *http.Request) {
+		wsURL := "ws://" + strings.TrimPrefix(server.URL, "http://") + "/devtools/page/ABC"
+		json.NewEncoder(w).Encode([]Target{{ID: "ABC", Type: "page", WebSocketDebuggerURL: wsURL}})
+	})
+
+	mux.HandleFunc("/devtools/page/ABC", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var req request
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			result := map[string]interface{}{}
+			if req.Method == methodNetworkGetResponseBody {
+				result = map[string]interface{}{"body": "<html></html>", "base64Encoded": false}
+			}
+			conn.WriteJSON(map[string]interface{}{"id": req.ID, "result": result})
+
+			if req.Method == methodPageNavigate {
+				for _, event := range events {
+					conn.WriteMessage(websocket.TextMessage, []byte(event))
+				}
+			}
+		}
+	})
+
+	return server
+}
+
+func TestCapture(t *testing.T) {
+	server := fakeBrowser(t)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	capture, err := Dial(ctx, strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+
+	require.NoError(t, capture.Navigate(ctx, "https://example.com/"))
+	for deadline := time.Now().Add(time.Second); len(capture.Events()) < len(events); {
+		require.True(t, time.Now().Before(deadline), "timed out waiting for events")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	h, err := capture.Stop()
+	require.NoError(t, err)
+	require.Len(t, h.Log.Entries, 1)
+	require.Equal(t, "https://example.com/", h.Log.Entries[0].Request.URL.String())
+	require.NotNil(t, h.Log.Entries[0].Response.Content.Text)
+	require.Equal(t, "<html></html>", *h.Log.Entries[0].Response.Content.Text)
+	require.Nil(t, h.Log.Entries[0].Response.Content.Encoding)
+	require.Equal(t, "ABC", capture.Events()[0].TargetID)
+
+	again, err := capture.Stop()
+	require.NoError(t, err)
+	require.True(t, h == again)
+}
@@ -0,0 +1,143 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+type request struct {
+	ID     int         `json:"id"`
+	Method string      `json:"method"`
+	Params interface{} `json:"params,omitempty"`
+}
+
+type response struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *responseError  `json:"error"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type client struct {
+	conn *websocket.Conn
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan response
+	readErr error
+
+	done chan struct{}
+}
+
+func dial(ctx context.Context, webSocketURL string, onEvent func(method string, params json.RawMessage)) (*client, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, webSocketURL, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial %q", webSocketURL)
+	}
+
+	c := &client{
+		conn:    conn,
+		pending: make(map[int]chan response),
+		done:    make(chan struct{}),
+	}
+	go c.read(onEvent)
+	return c, nil
+}
+
+func (c *client) read(onEvent func(method string, params json.RawMessage)) {
+	defer close(c.done)
+
+	for {
+		var resp response
+		if err := c.conn.ReadJSON(&resp); err != nil {
+			c.mu.Lock()
+			c.readErr = err
+			for id, ch := range c.pending {
+				close(ch)
+				delete(c.pending, id)
+			}
+			c.mu.Unlock()
+			return
+		}
+
+		if resp.ID == 0 {
+			onEvent(resp.Method, resp.Params)
+			continue
+		}
+
+		c.mu.Lock()
+		ch, ok := c.pending[resp.ID]
+		delete(c.pending, resp.ID)
+		c.mu.Unlock()
+
+		if ok {
+			ch <- resp
+		}
+	}
+}
+
+func (c *client) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	c.mu.Lock()
+	if c.readErr != nil {
+		err := c.readErr
+		c.mu.Unlock()
+		return nil, errors.Wrap(err, "connection closed")
+	}
+	c.nextID++
+	id := c.nextID
+	ch := make(chan response, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	c.writeMu.Lock()
+	err := c.conn.WriteJSON(request{ID: id, Method: method, Params: params})
+	c.writeMu.Unlock()
+	if err != nil {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return nil, errors.Wrapf(err, "failed to send %q", method)
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, errors.Errorf("connection closed waiting for %q", method)
+		}
+		if resp.Error != nil {
+			return nil, errors.Errorf("%q failed with code %d: %s", method, resp.Error.Code, resp.Error.Message)
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (c *client) close() error {
+	c.writeMu.Lock()
+	err := c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	c.writeMu.Unlock()
+
+	closeErr := c.conn.Close()
+	<-c.done
+
+	if err != nil {
+		return errors.Wrap(err, "failed to send close message")
+	}
+	return closeErr
+}
@@ -0,0 +1,60 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const targetTypePage = "page"
+
+type Target struct {
+	ID                   string `json:"id"`
+	Type                 string `json:"type"`
+	Title                string `json:"title"`
+	URL                  string `json:"url"`
+	WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+}
+
+func Targets(ctx context.Context, debuggingAddr string) ([]Target, error) {
+	listURL := fmt.Sprintf("http://%s/json/list", strings.TrimPrefix(debuggingAddr, "http://"))
+
+	req, err := http.NewRequest(http.MethodGet, listURL, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create request for %q", listURL)
+	}
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list targets at %q", listURL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %d listing targets at %q", resp.StatusCode, listURL)
+	}
+
+	var targets []Target
+	if err := json.NewDecoder(resp.Body).Decode(&targets); err != nil {
+		return nil, errors.Wrap(err, "failed to decode targets")
+	}
+	return targets, nil
+}
+
+func firstPageTarget(ctx context.Context, debuggingAddr string) (Target, error) {
+	targets, err := Targets(ctx, debuggingAddr)
+	if err != nil {
+		return Target{}, err
+	}
+
+	for _, target := range targets {
+		if target.Type == targetTypePage && target.WebSocketDebuggerURL != "" {
+			return target, nil
+		}
+	}
+	return Target{}, errors.Errorf("no page target available at %q", debuggingAddr)
+}
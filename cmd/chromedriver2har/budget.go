@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jordanpotter/chromedriver2har/budget"
+	"github.com/pkg/errors"
+)
+
+func runBudget(args []string) error {
+	flags := flag.NewFlagSet("budget", flag.ExitOnError)
+	configPath := flags.String("config", "", "YAML or JSON budget config file")
+	jsonOutput := flags.Bool("json", false, "print results as JSON")
+	flags.Parse(args)
+
+	if *configPath == "" {
+		return errors.New("expected a budget config")
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one HAR file")
+	}
+
+	config, err := budget.Load(*configPath)
+	if err != nil {
+		return err
+	}
+
+	h, err := readHAR(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	results := budget.Evaluate(h, config)
+
+	if *jsonOutput {
+		if err := writeJSON(os.Stdout, results); err != nil {
+			return errors.Wrap(err, "failed to write results")
+		}
+	} else {
+		for _, result := range results {
+			fmt.Println(result)
+		}
+	}
+
+	if len(budget.Failed(results)) > 0 {
+		return exitError(1)
+	}
+	return nil
+}
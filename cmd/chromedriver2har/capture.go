@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har/cdp"
+	"github.com/pkg/errors"
+)
+
+func runCapture(args []string) error {
+	flags := flag.NewFlagSet("capture", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:9222", "remote debugging address of the browser, or a target's WebSocket URL")
+	wait := flags.Duration("wait", 5*time.Second, "how long to record after navigating")
+	format := flags.String("format", "", "output format, har or warc (default from -o extension, otherwise har)")
+	output := flags.String("o", "-", "output file")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one URL")
+	}
+
+	outputFormat, err := captureFormat(*format, *output)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	capture, err := cdp.Dial(ctx, *addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to %q", *addr)
+	}
+
+	if err := capture.Navigate(ctx, flags.Arg(0)); err != nil {
+		capture.Stop()
+		return err
+	}
+	time.Sleep(*wait)
+
+	h, stopErr := capture.Stop()
+	if h == nil {
+		return stopErr
+	}
+	if err := writeCapture(*output, outputFormat, h, capture); err != nil {
+		return err
+	}
+	return stopErr
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"strings"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/warc"
+	"github.com/pkg/errors"
+)
+
+func runConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	filterFlags := newFilterFlags(flags)
+	format := flags.String("format", "", "output format, har or warc (default from -o extension, otherwise har)")
+	output := flags.String("o", "-", "output file")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one event log")
+	}
+
+	outputFormat, err := captureFormat(*format, *output)
+	if err != nil {
+		return err
+	}
+
+	f, err := filterFlags.filter()
+	if err != nil {
+		return err
+	}
+
+	matcher, err := f.Compile()
+	if err != nil {
+		return errors.Wrap(err, "failed to compile filter")
+	}
+
+	h, err := readEventLog(flags.Arg(0), matcher.Option())
+	if err != nil {
+		return err
+	}
+
+	return writeCapture(*output, outputFormat, h, nil)
+}
+
+// captureFormat resolves the -format flag of commands that write a capture,
+// defaulting from the output file's extension.
+func captureFormat(format, output string) (string, error) {
+	if format == "" {
+		format = "har"
+		lowerOutput := strings.ToLower(output)
+		if strings.HasSuffix(lowerOutput, ".warc") || strings.HasSuffix(lowerOutput, ".warc.gz") {
+			format = "warc"
+		}
+	}
+	if format != "har" && format != "warc" {
+		return "", errors.Errorf("unknown format %q", format)
+	}
+	return format, nil
+}
+
+// writeCapture writes h as a HAR or a WARC. WARC response records take their
+// bodies from bodies when it's set, and gzip when the output ends in .gz.
+func writeCapture(output, format string, h *har.HAR, bodies chromedriver2har.BodyProvider) error {
+	if format == "warc" {
+		opts := warc.Options{Bodies: bodies, Gzip: strings.HasSuffix(strings.ToLower(output), ".gz")}
+		return writeOutput(output, func(w io.Writer) error {
+			return warc.Write(w, h, opts)
+		})
+	}
+	return writeHAR(output, h)
+}
+
+func readEventLog(path string, opts ...chromedriver2har.Option) (*har.HAR, error) {
+	f, err := openFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %q", path)
+	}
+	defer f.Close()
+
+	opts = append(opts, chromedriver2har.WithWarnings(warn))
+	h, err := chromedriver2har.FromEventSource(chromedriver2har.NewJSONSource(f), opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to convert %q", path)
+	}
+	return h, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jordanpotter/chromedriver2har/diff"
+	"github.com/pkg/errors"
+)
+
+func runDiff(args []string) error {
+	var ignoredHeaders stringsFlag
+
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	jsonOutput := flags.Bool("json", false, "print changes as JSON")
+	timeThreshold := flags.Float64("time", 100, "minimum timing change to report in milliseconds")
+	sizeThreshold := flags.Int("size", 1024, "minimum size change to report in bytes")
+	ignoreQuery := flags.Bool("ignore-query", false, "match entries on URLs without query strings")
+	flags.Var(&ignoredHeaders, "ignore-header", "response header to ignore in addition to the defaults (repeatable)")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		return errors.New("expected a baseline and a current HAR file")
+	}
+
+	baseline, err := readHAR(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	current, err := readHAR(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	opts := diff.Options{
+		TimeThreshold: timeThreshold,
+		SizeThreshold: sizeThreshold,
+		IgnoreQuery:   *ignoreQuery,
+	}
+	if len(ignoredHeaders) > 0 {
+		opts.IgnoreHeaders = append(diff.DefaultIgnoredHeaders(), ignoredHeaders...)
+	}
+
+	changes := diff.Diff(baseline, current, opts)
+
+	if *jsonOutput {
+		if err := writeJSON(os.Stdout, changes); err != nil {
+			return errors.Wrap(err, "failed to write changes")
+		}
+	} else {
+		for _, change := range changes {
+			fmt.Println(change)
+		}
+	}
+
+	if len(changes) > 0 {
+		return exitError(1)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jordanpotter/chromedriver2har/export"
+	"github.com/jordanpotter/chromedriver2har/filter"
+	"github.com/jordanpotter/chromedriver2har/redact"
+	"github.com/pkg/errors"
+)
+
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	filterFlags := newFilterFlags(flags)
+	format := flags.String("format", string(export.FormatCurl), "export format: curl, go or http")
+	redactDefaults := flags.Bool("redact", false, "redact default sensitive headers and cookies")
+	output := flags.String("o", "-", "output file")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one HAR file")
+	}
+
+	f, err := filterFlags.filter()
+	if err != nil {
+		return err
+	}
+
+	var opts export.Options
+	if *redactDefaults {
+		if opts.Redactor, err = redact.New(redact.DefaultConfig()); err != nil {
+			return errors.Wrap(err, "failed to create redactor")
+		}
+	}
+
+	h, err := readHAR(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	h, err = filter.Apply(h, f)
+	if err != nil {
+		return errors.Wrap(err, "failed to filter HAR")
+	}
+
+	// Each Go export is a complete main package, so several can't share a file.
+	if export.Format(*format) == export.FormatGo && len(h.Log.Entries) > 1 {
+		return errors.Errorf("go format exports a single entry but %d matched; narrow them down with the filter flags", len(h.Log.Entries))
+	}
+
+	exported := make([]string, 0, len(h.Log.Entries))
+	for _, entry := range h.Log.Entries {
+		s, err := export.Export(entry, export.Format(*format), opts)
+		if err != nil {
+			return errors.Wrapf(err, "failed to export %s", entry.Request.URL.String())
+		}
+		exported = append(exported, strings.TrimSuffix(s, "\n"))
+	}
+
+	return writeOutput(*output, func(w io.Writer) error {
+		for i, s := range exported {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			if _, err := fmt.Fprintln(w, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har/filter"
+	"github.com/pkg/errors"
+)
+
+type filterFlags struct {
+	urlGlobs, urlRegexps, hosts, resourceTypes, mimeTypes, statusClasses, pages stringsFlag
+	after, before, party                                                        *string
+}
+
+func newFilterFlags(flags *flag.FlagSet) *filterFlags {
+	f := &filterFlags{
+		after:  flags.String("after", "", "keep entries started at or after this RFC3339 time"),
+		before: flags.String("before", "", "keep entries started at or before this RFC3339 time"),
+		party:  flags.String("party", "", "keep only first or third party entries"),
+	}
+	flags.Var(&f.urlGlobs, "url", "URL glob to keep, * and ? are wildcards (repeatable)")
+	flags.Var(&f.urlRegexps, "url-regexp", "URL regular expression to keep (repeatable)")
+	flags.Var(&f.hosts, "host", "host to keep, including subdomains (repeatable)")
+	flags.Var(&f.resourceTypes, "type", "resource type to keep, e.g. xhr or image (repeatable)")
+	flags.Var(&f.mimeTypes, "mime", "MIME type to keep, e.g. application/json or image/* (repeatable)")
+	flags.Var(&f.statusClasses, "status", "status class to keep, e.g. 4xx (repeatable)")
+	flags.Var(&f.pages, "page", "page ID to keep (repeatable)")
+	return f
+}
+
+func (f *filterFlags) filter() (filter.Filter, error) {
+	after, err := parseFilterTime(*f.after)
+	if err != nil {
+		return filter.Filter{}, errors.Wrap(err, "invalid -after")
+	}
+
+	before, err := parseFilterTime(*f.before)
+	if err != nil {
+		return filter.Filter{}, errors.Wrap(err, "invalid -before")
+	}
+
+	return filter.Filter{
+		URLGlobs:      f.urlGlobs,
+		URLRegexps:    f.urlRegexps,
+		Hosts:         f.hosts,
+		ResourceTypes: f.resourceTypes,
+		MIMETypes:     f.mimeTypes,
+		StatusClasses: f.statusClasses,
+		After:         after,
+		Before:        before,
+		Pages:         f.pages,
+		Party:         *f.party,
+	}, nil
+}
+
+func parseFilterTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func runFilter(args []string) error {
+	flags := flag.NewFlagSet("filter", flag.ExitOnError)
+	filterFlags := newFilterFlags(flags)
+	output := flags.String("o", "-", "output HAR file")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one HAR file")
+	}
+
+	f, err := filterFlags.filter()
+	if err != nil {
+		return err
+	}
+
+	h, err := readHAR(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	filtered, err := filter.Apply(h, f)
+	if err != nil {
+		return errors.Wrap(err, "failed to filter HAR")
+	}
+	return writeHAR(*output, filtered)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/pkg/errors"
+)
+
+func openFile(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return ioutil.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+func readFile(path string) ([]byte, error) {
+	f, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
+
+func readHAR(path string) (*har.HAR, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %q", path)
+	}
+
+	var h har.HAR
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %q", path)
+	}
+	return &h, nil
+}
+
+func writeHAR(path string, h *har.HAR) error {
+	return writeOutput(path, func(w io.Writer) error {
+		return writeJSON(w, h)
+	})
+}
+
+func writeOutput(path string, write func(io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %q", path)
+	}
+	defer f.Close()
+
+	if err := write(f); err != nil {
+		return errors.Wrapf(err, "failed to write %q", path)
+	}
+	return f.Close()
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(v)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"io"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/loadtest"
+	"github.com/pkg/errors"
+)
+
+func runLoadTest(args []string) error {
+	var pages stringsFlag
+
+	flags := flag.NewFlagSet("loadtest", flag.ExitOnError)
+	format := flags.String("format", "k6", "output format: k6 or vegeta")
+	output := flags.String("o", "-", "output file")
+	flags.Var(&pages, "page", "page ID to include (repeatable)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one HAR file")
+	}
+
+	var write func(io.Writer, *har.HAR, loadtest.Options) error
+	switch *format {
+	case "k6":
+		write = loadtest.K6
+	case "vegeta":
+		write = loadtest.Vegeta
+	default:
+		return errors.Errorf("unknown format %q", *format)
+	}
+
+	h, err := readHAR(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*output, func(w io.Writer) error {
+		return write(w, h, loadtest.Options{Pages: pages})
+	})
+}
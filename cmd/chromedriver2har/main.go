@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"budget":    {"budget -config budget.yaml [-json] <file.har>", runBudget},
+	"capture":   {"capture [-addr host:port|ws://url] [-wait duration] [-format har|warc] [-o file] <url>", runCapture},
+	"convert":   {"convert [filter flags] [-format har|warc] [-o file] <events.json|trace.json>", runConvert},
+	"diff":      {"diff [-json] [-time ms] [-size bytes] [-ignore-query] [-ignore-header name] <baseline.har> <current.har>", runDiff},
+	"export":    {"export [filter flags] [-format curl|go|http] [-redact] [-o file] <file.har>", runExport},
+	"filter":    {"filter [-url glob] [-url-regexp regexp] [-host host] [-type type] [-mime type] [-status 4xx] [-page id] [-after time] [-before time] [-party first|third] [-o file.har] <file.har>", runFilter},
+	"loadtest":  {"loadtest [-format k6|vegeta] [-page id] [-o file] <file.har>", runLoadTest},
+	"merge":     {"merge [-group] [-o file.har] <file.har|events.json>...", runMerge},
+	"mock":      {"mock [filter flags] [-package name] [-match-body] [-o file.go] <file.har>", runMock},
+	"openapi":   {"openapi [filter flags] [-format json|yaml] [-title title] [-version version] [-o file] <file.har>", runOpenAPI},
+	"redact":    {"redact [-config file.json] [-header name] [-cookie name] [-query name] [-body-path path] [-pattern regexp] [-o file.har] <file.har>", runRedact},
+	"replay":    {"replay [-addr host:port] [-match-body] [-ignore-host] [-unmatched not-found|error|nearest] [-latency] [-ca-cert file -ca-key file] [-write-ca file] <file.har>", runReplay},
+	"security":  {"security [-json] [-expiry duration] [-baseline report.json] <file.har>", runSecurity},
+	"stats":     {"stats [-json] [-slowest n] <file.har>", runStats},
+	"validate":  {"validate [-json] <file.har>...", runValidate},
+	"waterfall": {"waterfall [-format svg|html] [-width px] [-o file] <file.har>", runWaterfall},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err := cmd.run(os.Args[2:]); err != nil {
+		if exitErr, ok := err.(exitError); ok {
+			os.Exit(int(exitErr))
+		}
+		fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "usage: chromedriver2har <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[name].usage)
+	}
+}
+
+// warn reports a problem that doesn't stop the command.
+func warn(err error) {
+	fmt.Fprintf(os.Stderr, "%s: warning: %v\n", os.Args[1], err)
+}
+
+type exitError int
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"path/filepath"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/merge"
+	"github.com/pkg/errors"
+)
+
+func runMerge(args []string) error {
+	flags := flag.NewFlagSet("merge", flag.ExitOnError)
+	group := flags.Bool("group", false, "group each input's entries under a single page")
+	output := flags.String("o", "-", "output HAR file")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		return errors.New("expected at least one HAR file or event log")
+	}
+
+	sources := make([]merge.Source, 0, flags.NArg())
+	for _, path := range flags.Args() {
+		source, err := readMergeSource(path)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, source)
+	}
+
+	h, err := merge.Merge(sources, merge.Options{GroupBySource: *group})
+	if err != nil {
+		return errors.Wrap(err, "failed to merge")
+	}
+	return writeHAR(*output, h)
+}
+
+func readMergeSource(path string) (merge.Source, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return merge.Source{}, errors.Wrapf(err, "failed to read %q", path)
+	}
+
+	name := filepath.Base(path)
+
+	var h har.HAR
+	if isHAR(data) {
+		if err := json.Unmarshal(data, &h); err != nil {
+			return merge.Source{}, errors.Wrapf(err, "failed to unmarshal %q", path)
+		}
+		return merge.Source{Name: name, HAR: &h}, nil
+	}
+
+	return merge.FromEventSource(name, chromedriver2har.NewJSONSource(bytes.NewReader(data)), chromedriver2har.WithWarnings(warn))
+}
+
+func isHAR(data []byte) bool {
+	var v struct {
+		Log json.RawMessage `json:"log"`
+	}
+	return json.Unmarshal(data, &v) == nil && v.Log != nil
+}
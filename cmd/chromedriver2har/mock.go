@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"io"
+
+	"github.com/jordanpotter/chromedriver2har/filter"
+	"github.com/jordanpotter/chromedriver2har/mockgen"
+	"github.com/pkg/errors"
+)
+
+func runMock(args []string) error {
+	flags := flag.NewFlagSet("mock", flag.ExitOnError)
+	filterFlags := newFilterFlags(flags)
+	packageName := flags.String("package", "mocks", "package name of the generated source")
+	matchBody := flags.Bool("match-body", false, "match requests on their bodies as well as method and URL")
+	output := flags.String("o", "-", "output Go file")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one HAR file")
+	}
+
+	f, err := filterFlags.filter()
+	if err != nil {
+		return err
+	}
+
+	h, err := readHAR(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	h, err = filter.Apply(h, f)
+	if err != nil {
+		return errors.Wrap(err, "failed to filter HAR")
+	}
+
+	return writeOutput(*output, func(w io.Writer) error {
+		return mockgen.Generate(w, h, mockgen.Options{Package: *packageName, MatchBody: *matchBody})
+	})
+}
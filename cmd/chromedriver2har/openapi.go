@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/jordanpotter/chromedriver2har/filter"
+	"github.com/jordanpotter/chromedriver2har/openapi"
+	"github.com/pkg/errors"
+)
+
+func runOpenAPI(args []string) error {
+	flags := flag.NewFlagSet("openapi", flag.ExitOnError)
+	filterFlags := newFilterFlags(flags)
+	format := flags.String("format", "", "output format, json or yaml (default from -o extension, otherwise yaml)")
+	title := flags.String("title", "", "title of the API description")
+	version := flags.String("version", "", "version of the API description")
+	output := flags.String("o", "-", "output file")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one HAR file")
+	}
+
+	if *format == "" {
+		*format = "yaml"
+		if strings.EqualFold(filepath.Ext(*output), ".json") {
+			*format = "json"
+		}
+	}
+	if *format != "json" && *format != "yaml" {
+		return errors.Errorf("unknown format %q", *format)
+	}
+
+	f, err := filterFlags.filter()
+	if err != nil {
+		return err
+	}
+
+	h, err := readHAR(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	h, err = filter.Apply(h, f)
+	if err != nil {
+		return errors.Wrap(err, "failed to filter HAR")
+	}
+
+	doc := openapi.Infer(h, openapi.Options{Title: *title, Version: *version})
+	return writeOutput(*output, func(w io.Writer) error {
+		if *format == "json" {
+			return writeJSON(w, doc)
+		}
+		data, err := doc.YAML()
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	})
+}
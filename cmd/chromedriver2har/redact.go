@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+
+	"github.com/jordanpotter/chromedriver2har/redact"
+	"github.com/pkg/errors"
+)
+
+func runRedact(args []string) error {
+	var headers, cookies, queryParams, bodyPaths, patterns stringsFlag
+
+	flags := flag.NewFlagSet("redact", flag.ExitOnError)
+	configPath := flags.String("config", "", "JSON redaction config file")
+	defaults := flags.Bool("defaults", true, "redact default sensitive headers and cookies")
+	salt := flags.String("salt", "", "salt for placeholder hashes (random if unset)")
+	output := flags.String("o", "-", "output HAR file")
+	flags.Var(&headers, "header", "header name to redact (repeatable)")
+	flags.Var(&cookies, "cookie", "cookie name to redact, * for all (repeatable)")
+	flags.Var(&queryParams, "query", "query or form parameter to redact, * for all (repeatable)")
+	flags.Var(&bodyPaths, "body-path", "dot separated JSON body path to redact, * matches any key (repeatable)")
+	flags.Var(&patterns, "pattern", "regular expression to redact anywhere (repeatable)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one HAR file")
+	}
+
+	config := redact.Config{}
+	if *defaults {
+		config = redact.DefaultConfig()
+	}
+
+	if *configPath != "" {
+		data, err := readFile(*configPath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %q", *configPath)
+		}
+
+		var fileConfig redact.Config
+		if err := json.Unmarshal(data, &fileConfig); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal %q", *configPath)
+		}
+		config = mergeRedactConfig(config, fileConfig)
+	}
+
+	config = mergeRedactConfig(config, redact.Config{
+		Headers:     headers,
+		Cookies:     cookies,
+		QueryParams: queryParams,
+		BodyPaths:   bodyPaths,
+		Patterns:    patterns,
+		Salt:        *salt,
+	})
+
+	redactor, err := redact.New(config)
+	if err != nil {
+		return errors.Wrap(err, "failed to create redactor")
+	}
+
+	h, err := readHAR(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	redactor.Redact(h)
+	return writeHAR(*output, h)
+}
+
+func mergeRedactConfig(base, override redact.Config) redact.Config {
+	base.Headers = append(base.Headers, override.Headers...)
+	base.Cookies = append(base.Cookies, override.Cookies...)
+	base.QueryParams = append(base.QueryParams, override.QueryParams...)
+	base.BodyPaths = append(base.BodyPaths, override.BodyPaths...)
+	base.Patterns = append(base.Patterns, override.Patterns...)
+	if override.Salt != "" {
+		base.Salt = override.Salt
+	}
+	return base
+}
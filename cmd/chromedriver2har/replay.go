@@ -0,0 +1,71 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/jordanpotter/chromedriver2har/replay"
+	"github.com/pkg/errors"
+)
+
+func runReplay(args []string) error {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	matchBody := flags.Bool("match-body", false, "match requests on their bodies as well as method and URL")
+	ignoreHost := flags.Bool("ignore-host", false, "match requests on path and query only")
+	unmatched := flags.String("unmatched", string(replay.UnmatchedNotFound), "unmatched request handling: not-found, error or nearest")
+	latency := flags.Bool("latency", false, "simulate recorded latency")
+	caCert := flags.String("ca-cert", "", "PEM CA certificate that signs HTTPS certificates for CONNECT tunnels (generated if unset)")
+	caKey := flags.String("ca-key", "", "PEM private key for -ca-cert")
+	writeCA := flags.String("write-ca", "", "write the CA certificate as PEM for clients to trust")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one HAR file")
+	}
+
+	h, err := readHAR(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	opts := replay.Options{
+		MatchBody:       *matchBody,
+		IgnoreHost:      *ignoreHost,
+		Unmatched:       replay.UnmatchedMode(*unmatched),
+		SimulateLatency: *latency,
+	}
+	if *caCert != "" || *caKey != "" {
+		ca, err := tls.LoadX509KeyPair(*caCert, *caKey)
+		if err != nil {
+			return errors.Wrap(err, "failed to load CA")
+		}
+		opts.CA = &ca
+	}
+
+	server, err := replay.New(h, opts)
+	if err != nil {
+		return errors.Wrap(err, "failed to create replay server")
+	}
+
+	if *writeCA != "" {
+		caCert, err := server.CACertificate()
+		if err != nil {
+			return err
+		}
+		err = writeOutput(*writeCA, func(w io.Writer) error {
+			return pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "replaying %d entries on %s\n", len(h.Log.Entries), *addr)
+	return http.ListenAndServe(*addr, server)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har/security"
+	"github.com/pkg/errors"
+)
+
+// Exit statuses of the security command. New mixed content takes precedence,
+// so builds can fail on it alone.
+const (
+	exitNewMixedContent = 1
+	exitTLSIssues       = 3
+)
+
+type securityReport struct {
+	MixedContent []security.MixedContent `json:"mixedContent"`
+	TLS          []security.HostTLS      `json:"tls"`
+}
+
+func runSecurity(args []string) error {
+	flags := flag.NewFlagSet("security", flag.ExitOnError)
+	jsonOutput := flags.Bool("json", false, "print report as JSON")
+	expiryWindow := flags.Duration("expiry", 30*24*time.Hour, "report certificates expiring within this window")
+	baselinePath := flags.String("baseline", "", "JSON report of a previous run; only mixed content missing from it fails")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one HAR file")
+	}
+
+	h, err := readHAR(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var baseline securityReport
+	if *baselinePath != "" {
+		data, err := readFile(*baselinePath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %q", *baselinePath)
+		}
+		if err := json.Unmarshal(data, &baseline); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal %q", *baselinePath)
+		}
+	}
+
+	tls, err := security.TLSIssues(h, security.TLSOptions{ExpiryWindow: *expiryWindow})
+	if err != nil {
+		return errors.Wrap(err, "failed to inspect TLS")
+	}
+
+	report := securityReport{
+		MixedContent: security.FindMixedContent(h),
+		TLS:          tls,
+	}
+	newMixedContent := security.NewMixedContent(report.MixedContent, baseline.MixedContent)
+
+	if *jsonOutput {
+		if err := writeJSON(os.Stdout, report); err != nil {
+			return errors.Wrap(err, "failed to write report")
+		}
+	} else {
+		for _, mixedContent := range report.MixedContent {
+			fmt.Printf("%s: %s %s (document %s)\n", mixedContent.Kind, mixedContent.Method, mixedContent.URL, mixedContent.DocumentURL)
+		}
+		for _, hostTLS := range report.TLS {
+			for _, issue := range hostTLS.Issues {
+				fmt.Printf("tls: %s: %s\n", hostTLS.Host, issue)
+			}
+		}
+	}
+
+	switch {
+	case len(newMixedContent) > 0:
+		return exitError(exitNewMixedContent)
+	case len(report.TLS) > 0:
+		return exitError(exitTLSIssues)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jordanpotter/chromedriver2har/stats"
+	"github.com/pkg/errors"
+)
+
+func runStats(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	jsonOutput := flags.Bool("json", false, "print statistics as JSON")
+	slowest := flags.Int("slowest", 10, "number of slowest requests to list")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one HAR file")
+	}
+
+	h, err := readHAR(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	s := stats.Compute(h, stats.Options{Slowest: *slowest})
+
+	if *jsonOutput {
+		err = writeJSON(os.Stdout, s)
+	} else {
+		err = writeStatsTable(os.Stdout, s)
+	}
+	return errors.Wrap(err, "failed to write statistics")
+}
+
+func writeStatsTable(w io.Writer, s stats.Stats) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "requests\t%d\t\n", s.Requests)
+	fmt.Fprintf(tw, "bytes\t%d\t\n", s.Bytes)
+	fmt.Fprintf(tw, "cache hit ratio\t%.1f%%\t\n", s.CacheHitRatio*100)
+	fmt.Fprintf(tw, "compression ratio\t%.1f%%\t\n", s.CompressionRatio*100)
+	fmt.Fprintf(tw, "connection reuse\t%.1f%%\t\n", s.ConnectionReuseRate*100)
+
+	for _, section := range []struct {
+		title  string
+		groups []stats.Group
+	}{
+		{"resource type", s.ByResourceType},
+		{"domain", s.ByDomain},
+		{"status", s.ByStatus},
+	} {
+		fmt.Fprintf(tw, "\t\t\t\n%s\trequests\tbytes\t\n", section.title)
+		for _, group := range section.groups {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t\n", group.Name, group.Requests, group.Bytes)
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nslowest requests\n")
+	for _, request := range s.Slowest {
+		fmt.Fprintf(w, "%8.0f ms  %3d  %s %s\n", request.Time, request.Status, request.Method, request.URL)
+	}
+	return nil
+}
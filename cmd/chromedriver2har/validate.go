@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jordanpotter/chromedriver2har/validate"
+	"github.com/pkg/errors"
+)
+
+func runValidate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	jsonOutput := flags.Bool("json", false, "print findings as JSON")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		return errors.New("no HAR files given")
+	}
+
+	failed := false
+	findingsByFile := make(map[string][]validate.Finding, flags.NArg())
+	for _, path := range flags.Args() {
+		data, err := readFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %q", path)
+		}
+
+		findings, err := validate.ValidateJSON(data)
+		if err != nil {
+			return errors.Wrapf(err, "failed to validate %q", path)
+		}
+
+		findingsByFile[path] = findings
+		failed = failed || validate.HasErrors(findings)
+
+		if !*jsonOutput {
+			for _, finding := range findings {
+				fmt.Printf("%s: %s\n", path, finding)
+			}
+		}
+	}
+
+	if *jsonOutput {
+		if err := writeJSON(os.Stdout, findingsByFile); err != nil {
+			return errors.Wrap(err, "failed to write findings")
+		}
+	}
+
+	if failed {
+		return exitError(1)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/waterfall"
+	"github.com/pkg/errors"
+)
+
+func runWaterfall(args []string) error {
+	flags := flag.NewFlagSet("waterfall", flag.ExitOnError)
+	format := flags.String("format", "", "output format, svg or html (default from -o extension, otherwise html)")
+	width := flags.Int("width", 0, "chart width in pixels (default 1200)")
+	output := flags.String("o", "-", "output file")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one HAR file")
+	}
+
+	if *format == "" {
+		*format = "html"
+		if strings.EqualFold(filepath.Ext(*output), ".svg") {
+			*format = "svg"
+		}
+	}
+
+	var render func(io.Writer, *har.HAR, waterfall.Options) error
+	switch *format {
+	case "svg":
+		render = waterfall.SVG
+	case "html":
+		render = waterfall.HTML
+	default:
+		return errors.Errorf("unknown format %q", *format)
+	}
+
+	h, err := readHAR(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*output, func(w io.Writer) error {
+		return render(w, h, waterfall.Options{Width: *width})
+	})
+}
@@ -0,0 +1,64 @@
+package chromedriver2har
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+type eventTimestamp struct {
+	Timestamp *float64 `json:"timestamp"`
+}
+
+type eventRequestID struct {
+	RequestID string `json:"requestId"`
+}
+
+func normalizeEvents(events []Event) []Event {
+	type timedEvent struct {
+		event     Event
+		timestamp float64
+	}
+
+	seen := make(map[string]bool, len(events))
+	timedEvents := make([]timedEvent, 0, len(events))
+	lastTimestamp := 0.0
+
+	for _, event := range events {
+		key := event.target() + "\x00" + event.Method + "\x00" + string(event.Params)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		var data eventTimestamp
+		if err := json.Unmarshal(event.Params, &data); err == nil && data.Timestamp != nil {
+			lastTimestamp = *data.Timestamp
+		}
+		timedEvents = append(timedEvents, timedEvent{event: event, timestamp: lastTimestamp})
+	}
+
+	sort.SliceStable(timedEvents, func(i, j int) bool {
+		return timedEvents[i].timestamp < timedEvents[j].timestamp
+	})
+
+	normalized := make([]Event, 0, len(timedEvents))
+	for _, timedEvent := range timedEvents {
+		normalized = append(normalized, timedEvent.event)
+	}
+	return normalized
+}
+
+func requestID(params json.RawMessage) string {
+	var data eventRequestID
+	if err := json.Unmarshal(params, &data); err != nil {
+		return ""
+	}
+	return data.RequestID
+}
+
+type missingRequestError string
+
+func (e missingRequestError) Error() string {
+	return fmt.Sprintf("missing entry for request %q", string(e))
+}
@@ -0,0 +1,51 @@
+package chromedriver2har
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutOfOrderEvents(t *testing.T) {
+	first := requestEvents("main", "1.1", "https://example.com/", 10)
+	second := requestEvents("main", "1.2", "https://example.com/app.js", 10.5)
+	dataReceived := Event{
+		Method:   MethodNetworkDataReceived,
+		Params:   json.RawMessage(`{"requestId":"1.2","timestamp":10.4,"dataLength":50,"encodedDataLength":50}`),
+		TargetID: "main",
+	}
+	orphan := Event{
+		Method:   MethodNetworkLoadingFinished,
+		Params:   json.RawMessage(`{"requestId":"0.9","timestamp":9.9,"encodedDataLength":10}`),
+		TargetID: "main",
+	}
+
+	events := []Event{
+		orphan,
+		second[2], second[1], dataReceived, dataReceived,
+		first[1], first[0], first[2],
+		second[0],
+	}
+
+	h, err := FromEventSource(NewSliceSource(events))
+	require.NoError(t, err)
+	require.Len(t, h.Log.Entries, 2)
+	require.Equal(t, "https://example.com/", h.Log.Entries[0].Request.URL.String())
+	require.Equal(t, "https://example.com/app.js", h.Log.Entries[1].Request.URL.String())
+	require.Equal(t, 50, h.Log.Entries[1].Response.Content.Size)
+}
+
+func TestLostRequestWillBeSent(t *testing.T) {
+	kept := requestEvents("main", "1.1", "https://example.com/", 10)
+	lost := requestEvents("main", "1.2", "https://example.com/app.js", 10.5)
+
+	var warnings []string
+	h, err := FromEventSource(NewSliceSource(append(kept, lost[1:]...)), WithWarnings(func(err error) {
+		warnings = append(warnings, err.Error())
+	}))
+	require.NoError(t, err)
+	require.Len(t, h.Log.Entries, 1)
+	require.Equal(t, "https://example.com/", h.Log.Entries[0].Request.URL.String())
+	require.Equal(t, []string{`dropped 2 events for request "1.2", which has no Network.requestWillBeSent`}, warnings)
+}
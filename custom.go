@@ -0,0 +1,21 @@
+package chromedriver2har
+
+const (
+	CustomRequestID              = "_requestId"
+	CustomDocumentURL            = "_documentURL"
+	CustomResourceType           = "_resourceType"
+	CustomFetchOrigin            = "_fetchOrigin"
+	CustomInitiator              = "_initiator"
+	CustomServiceWorkerRequestID = "_serviceWorkerRequestId"
+	CustomSatisfiedRequestID     = "_satisfiedRequestId"
+	CustomPushed                 = "_pushed"
+	CustomSecurityState          = "_securityState"
+	CustomSecurityDetails        = "_securityDetails"
+	CustomMixedContentType       = "_mixedContentType"
+	CustomMixedContent           = "_mixedContent"
+
+	CustomFirstPaint             = "_firstPaint"
+	CustomFirstContentfulPaint   = "_firstContentfulPaint"
+	CustomFirstMeaningfulPaint   = "_firstMeaningfulPaint"
+	CustomLargestContentfulPaint = "_largestContentfulPaint"
+)
@@ -0,0 +1,248 @@
+package diff
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+)
+
+const (
+	defaultTimeThreshold = 100
+	defaultSizeThreshold = 1024
+)
+
+type ChangeKind string
+
+const (
+	Added         ChangeKind = "added"
+	Removed       ChangeKind = "removed"
+	StatusChanged ChangeKind = "status"
+	SizeChanged   ChangeKind = "size"
+	TimeChanged   ChangeKind = "time"
+	HeaderChanged ChangeKind = "header"
+)
+
+var defaultIgnoredHeaders = []string{
+	"age",
+	"cf-ray",
+	"content-length",
+	"date",
+	"etag",
+	"expires",
+	"last-modified",
+	"report-to",
+	"server-timing",
+	"set-cookie",
+	"x-request-id",
+}
+
+func DefaultIgnoredHeaders() []string {
+	return append([]string(nil), defaultIgnoredHeaders...)
+}
+
+type Options struct {
+	// TimeThreshold is the minimum change in milliseconds to report; nil
+	// means 100 and 0 reports every change.
+	TimeThreshold *float64
+	// SizeThreshold is the minimum change in bytes to report; nil means 1024
+	// and 0 reports every change.
+	SizeThreshold *int
+	// IgnoreQuery matches entries on URLs without their query strings.
+	IgnoreQuery bool
+	// IgnoreHeaders replaces the default list of volatile response headers.
+	IgnoreHeaders []string
+}
+
+type Change struct {
+	Kind   ChangeKind `json:"kind"`
+	Method string     `json:"method"`
+	URL    string     `json:"url"`
+	Header string     `json:"header,omitempty"`
+	Before string     `json:"before,omitempty"`
+	After  string     `json:"after,omitempty"`
+	Delta  float64    `json:"delta,omitempty"`
+}
+
+func (c Change) String() string {
+	request := c.Method + " " + c.URL
+	switch c.Kind {
+	case Added, Removed:
+		return fmt.Sprintf("%s: %s", c.Kind, request)
+	case HeaderChanged:
+		return fmt.Sprintf("%s: %s: %s %q -> %q", c.Kind, request, c.Header, c.Before, c.After)
+	case SizeChanged:
+		return fmt.Sprintf("%s: %s: %s -> %s bytes (%+.0f)", c.Kind, request, c.Before, c.After, c.Delta)
+	case TimeChanged:
+		return fmt.Sprintf("%s: %s: %s -> %s ms (%+.0f)", c.Kind, request, c.Before, c.After, c.Delta)
+	default:
+		return fmt.Sprintf("%s: %s: %s -> %s", c.Kind, request, c.Before, c.After)
+	}
+}
+
+func Diff(baseline, current *har.HAR, opts Options) []Change {
+	timeThreshold := float64(defaultTimeThreshold)
+	if opts.TimeThreshold != nil {
+		timeThreshold = *opts.TimeThreshold
+	}
+	sizeThreshold := defaultSizeThreshold
+	if opts.SizeThreshold != nil {
+		sizeThreshold = *opts.SizeThreshold
+	}
+	ignoredHeaders := opts.IgnoreHeaders
+	if ignoredHeaders == nil {
+		ignoredHeaders = defaultIgnoredHeaders
+	}
+	ignored := make(map[string]bool, len(ignoredHeaders))
+	for _, name := range ignoredHeaders {
+		ignored[strings.ToLower(name)] = true
+	}
+
+	baselineEntries := entriesByKey(baseline, opts.IgnoreQuery)
+	currentEntries := entriesByKey(current, opts.IgnoreQuery)
+
+	changes := make([]Change, 0)
+	for _, key := range entryKeys(baselineEntries, currentEntries) {
+		before, after := baselineEntries[key], currentEntries[key]
+		for i := 0; i < len(before) || i < len(after); i++ {
+			switch {
+			case i >= len(after):
+				changes = append(changes, newChange(Removed, before[i]))
+			case i >= len(before):
+				changes = append(changes, newChange(Added, after[i]))
+			default:
+				changes = append(changes, entryChanges(before[i], after[i], timeThreshold, sizeThreshold, ignored)...)
+			}
+		}
+	}
+	return changes
+}
+
+func entryChanges(before, after har.Entry, timeThreshold float64, sizeThreshold int, ignoredHeaders map[string]bool) []Change {
+	var changes []Change
+
+	if before.Response.Status != after.Response.Status {
+		change := newChange(StatusChanged, after)
+		change.Before = fmt.Sprint(before.Response.Status)
+		change.After = fmt.Sprint(after.Response.Status)
+		changes = append(changes, change)
+	}
+
+	sizeDelta := after.Response.Content.Size - before.Response.Content.Size
+	if sizeDelta != 0 && (sizeDelta >= sizeThreshold || -sizeDelta >= sizeThreshold) {
+		change := newChange(SizeChanged, after)
+		change.Before = fmt.Sprint(before.Response.Content.Size)
+		change.After = fmt.Sprint(after.Response.Content.Size)
+		change.Delta = float64(sizeDelta)
+		changes = append(changes, change)
+	}
+
+	timeDelta := after.Time - before.Time
+	if timeDelta != 0 && math.Abs(timeDelta) >= timeThreshold {
+		change := newChange(TimeChanged, after)
+		change.Before = fmt.Sprintf("%.0f", before.Time)
+		change.After = fmt.Sprintf("%.0f", after.Time)
+		change.Delta = timeDelta
+		changes = append(changes, change)
+	}
+
+	beforeHeaders := headerValues(before.Response.Headers, ignoredHeaders)
+	afterHeaders := headerValues(after.Response.Headers, ignoredHeaders)
+	for _, name := range headerNames(beforeHeaders, afterHeaders) {
+		if beforeHeaders[name] == afterHeaders[name] {
+			continue
+		}
+		change := newChange(HeaderChanged, after)
+		change.Header = name
+		change.Before = beforeHeaders[name]
+		change.After = afterHeaders[name]
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+func newChange(kind ChangeKind, entry har.Entry) Change {
+	return Change{
+		Kind:   kind,
+		Method: entry.Request.Method,
+		URL:    entry.Request.URL.String(),
+	}
+}
+
+func entriesByKey(h *har.HAR, ignoreQuery bool) map[string][]har.Entry {
+	entries := make(map[string][]har.Entry)
+	for _, entry := range h.Log.Entries {
+		key := entry.Request.Method + " " + normalizeURL(entry.Request.URL.URL, ignoreQuery)
+		entries[key] = append(entries[key], entry)
+	}
+	return entries
+}
+
+// URLs are compared without fragments, default ports or query parameter
+// ordering, since none of these change what the server is asked for.
+func normalizeURL(u url.URL, ignoreQuery bool) string {
+	u.Fragment = ""
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if (u.Scheme == "http" && u.Port() == "80") || (u.Scheme == "https" && u.Port() == "443") {
+		u.Host = u.Hostname()
+	}
+	if ignoreQuery {
+		u.RawQuery = ""
+	} else {
+		u.RawQuery = u.Query().Encode()
+	}
+	return u.String()
+}
+
+func headerValues(headers []har.Header, ignored map[string]bool) map[string]string {
+	values := make(map[string][]string)
+	for _, header := range headers {
+		name := strings.ToLower(header.Name)
+		if ignored[name] || strings.HasPrefix(name, ":") {
+			continue
+		}
+		values[name] = append(values[name], header.Value)
+	}
+
+	joined := make(map[string]string, len(values))
+	for name, vals := range values {
+		joined[name] = strings.Join(vals, ", ")
+	}
+	return joined
+}
+
+func entryKeys(a, b map[string][]har.Entry) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	for key := range a {
+		seen[key] = true
+	}
+	for key := range b {
+		seen[key] = true
+	}
+	return sortedKeys(seen)
+}
+
+func headerNames(a, b map[string]string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	for name := range a {
+		seen[name] = true
+	}
+	for name := range b {
+		seen[name] = true
+	}
+	return sortedKeys(seen)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	sorted := make([]string, 0, len(m))
+	for key := range m {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,43 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/internal/hartest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	baseline := &har.HAR{Log: har.Log{Entries: []har.Entry{
+		hartest.NewEntry("GET", "https://example.com/").Status(200).ContentSize(5000).Time(120).ResponseHeader("Content-Type", "text/html").ResponseHeader("Date", "Mon").Build(),
+		hartest.NewEntry("GET", "https://example.com/api?b=2&a=1").Status(200).ContentSize(300).Time(80).Build(),
+		hartest.NewEntry("GET", "https://example.com/old.js").Status(200).ContentSize(100).Time(10).Build(),
+	}}}
+	current := &har.HAR{Log: har.Log{Entries: []har.Entry{
+		hartest.NewEntry("GET", "https://example.com:443/#top").Status(200).ContentSize(9000).Time(150).ResponseHeader("content-type", "text/html; charset=utf-8").ResponseHeader("Date", "Tue").Build(),
+		hartest.NewEntry("GET", "https://example.com/api?a=1&b=2").Status(500).ContentSize(300).Time(400).Build(),
+		hartest.NewEntry("POST", "https://example.com/api?a=1&b=2").Status(200).ContentSize(10).Time(50).Build(),
+	}}}
+
+	changes := Diff(baseline, current, Options{})
+	require.Equal(t, []Change{
+		{Kind: SizeChanged, Method: "GET", URL: "https://example.com:443/#top", Before: "5000", After: "9000", Delta: 4000},
+		{Kind: HeaderChanged, Method: "GET", URL: "https://example.com:443/#top", Header: "content-type", Before: "text/html", After: "text/html; charset=utf-8"},
+		{Kind: StatusChanged, Method: "GET", URL: "https://example.com/api?a=1&b=2", Before: "200", After: "500"},
+		{Kind: TimeChanged, Method: "GET", URL: "https://example.com/api?a=1&b=2", Before: "80", After: "400", Delta: 320},
+		{Kind: Removed, Method: "GET", URL: "https://example.com/old.js"},
+		{Kind: Added, Method: "POST", URL: "https://example.com/api?a=1&b=2"},
+	}, changes)
+
+	require.Equal(t, "status: GET https://example.com/api?a=1&b=2: 200 -> 500", changes[2].String())
+	require.Equal(t, "time: GET https://example.com/api?a=1&b=2: 80 -> 400 ms (+320)", changes[3].String())
+
+	timeThreshold, sizeThreshold := 1000.0, 10000
+	require.Len(t, Diff(baseline, current, Options{TimeThreshold: &timeThreshold, SizeThreshold: &sizeThreshold, IgnoreHeaders: []string{"content-type"}}), 4)
+
+	timeThreshold, sizeThreshold = 0, 0
+	changes = Diff(baseline, current, Options{TimeThreshold: &timeThreshold, SizeThreshold: &sizeThreshold, IgnoreHeaders: []string{}})
+	require.Len(t, changes, 8)
+	require.Equal(t, Change{Kind: TimeChanged, Method: "GET", URL: "https://example.com:443/#top", Before: "120", After: "150", Delta: 30}, changes[1])
+}
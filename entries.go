@@ -1,36 +1,82 @@
 package chromedriver2har
 
 import (
+	"encoding/base64"
 	"fmt"
+	"math"
+	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
-	"time"
 
-	"github.com/jordanpotter/har"
+	"github.com/jordanpotter/chromedriver2har/har"
 	"github.com/pkg/errors"
 )
 
-func harEntries(chromeLogEntries []ChromeLogEntry) ([]har.Entry, error) {
-	paramsByRequest, err := paramsByRequest(chromeLogEntries)
+const encodingBase64 = "base64"
+
+// Headers describing the recorded transfer rather than the content, which
+// would be wrong alongside a decoded body.
+var transferHeaders = map[string]bool{
+	"connection":        true,
+	"content-encoding":  true,
+	"content-length":    true,
+	"keep-alive":        true,
+	"transfer-encoding": true,
+}
+
+// harEntries creates an entry for each completed request in events. Entries
+// are filtered before their response bodies are looked up, so dropped entries
+// never cost a body fetch.
+func harEntries(pageRef, initiator string, events []Event, opts options) ([]har.Entry, error) {
+	paramsByRequest, orphansByRequest, err := paramsByRequest(events)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create params by request")
 	}
 
-	entries := make([]har.Entry, 0, len(paramsByRequest))
-	for requestID, params := range paramsByRequest {
-		if !params.completed() {
-			continue
+	orphanedRequests := make([]string, 0, len(orphansByRequest))
+	for requestID := range orphansByRequest {
+		orphanedRequests = append(orphanedRequests, requestID)
+	}
+	sort.Strings(orphanedRequests)
+	for _, requestID := range orphanedRequests {
+		opts.warnf("dropped %d events for request %q, which has no %s", len(orphansByRequest[requestID]), requestID, MethodNetworkRequestWillBeSent)
+	}
+
+	completedParams := make([]*requestParams, 0, len(paramsByRequest))
+	for _, params := range paramsByRequest {
+		if params.completed() {
+			completedParams = append(completedParams, params)
 		}
+	}
+
+	sort.Slice(completedParams, func(i, j int) bool {
+		return completedParams[i].networkRequestWillBeSent.Timestamp < completedParams[j].networkRequestWillBeSent.Timestamp
+	})
 
+	entries := make([]har.Entry, 0, len(completedParams))
+	for _, params := range completedParams {
+		requestID := params.networkRequestWillBeSent.RequestID
 		entry, err := harEntry(params)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to create har entry for request %q", requestID)
 		}
 
+		if pageRef != "" {
+			entry.PageRef = &pageRef
+		}
+		if initiator != "" {
+			entry.Custom[CustomInitiator] = initiator
+		}
+		if !opts.keep(entry) {
+			continue
+		}
+
+		harResponseBody(&entry.Response.Content, requestID, opts)
 		entries = append(entries, entry)
 	}
 
-	// TODO: sort entries
 	return entries, nil
 }
 
@@ -55,20 +101,65 @@ func harEntry(params *requestParams) (har.Entry, error) {
 		return har.Entry{}, errors.Wrap(err, "failed to create har timinhgs")
 	}
 
-	return har.Entry{
+	entry := har.Entry{
 		StartedDateTime: harEntryStartedDateTime(params),
 		Time:            harEntryTime(params),
 		Request:         request,
 		Response:        response,
 		Cache:           cache,
 		Timings:         timings,
-	}, nil
+		Connection:      harConnection(params),
+		Custom:          harEntryCustom(params),
+	}
+
+	if kind, ok := ClassifyMixedContent(entry, params.networkRequestWillBeSent.DocumentURL); ok {
+		entry.Custom[CustomMixedContent] = string(kind)
+	}
+
+	return entry, nil
+}
+
+func harConnection(params *requestParams) *string {
+	connectionID := params.networkResponseReceived.Response.ConnectionID
+	if connectionID == 0 {
+		return nil
+	}
+	connection := strconv.Itoa(connectionID)
+	return &connection
+}
+
+func harEntryCustom(params *requestParams) har.Custom {
+	request := params.networkRequestWillBeSent.Request
+	response := params.networkResponseReceived.Response
+
+	custom := har.Custom{
+		CustomRequestID:    params.networkRequestWillBeSent.RequestID,
+		CustomDocumentURL:  params.networkRequestWillBeSent.DocumentURL,
+		CustomResourceType: params.resourceType(),
+		CustomFetchOrigin:  fetchOrigin(params),
+		CustomInitiator:    initiator(params),
+	}
+
+	if params.pushed() {
+		custom[CustomPushed] = true
+	}
+
+	if request.MixedContentType != nil {
+		custom[CustomMixedContentType] = *request.MixedContentType
+	}
+
+	if response.SecurityState != "" {
+		custom[CustomSecurityState] = response.SecurityState
+	}
+	if response.SecurityDetails != nil {
+		custom[CustomSecurityDetails] = *response.SecurityDetails
+	}
+
+	return custom
 }
 
 func harEntryStartedDateTime(params *requestParams) har.Time {
-	wallTimeNanoseconds := params.networkRequestWillBeSent.WallTime * float64(time.Second) / float64(time.Nanosecond)
-	startedDateTime := time.Unix(0, int64(wallTimeNanoseconds))
-	return har.Time{startedDateTime}
+	return harTime(params.networkRequestWillBeSent.WallTime)
 }
 
 func harEntryTime(params *requestParams) float64 {
@@ -87,20 +178,26 @@ func harRequest(params *requestParams) (har.Request, error) {
 	}
 
 	bodySize := -1
+	var postData *har.PostData
 	if request.PostData != nil {
 		bodySize = len(*request.PostData)
+		postData = harPostData(request.Headers, *request.PostData)
 	}
 
-	headersSize := harRequestHeadersSize(request.Method, safeStringDereference(response.Protocol), *requestURL, request.Headers)
+	httpVersion := harHTTPVersion(safeStringDereference(response.Protocol))
+	headersSize := -1
+	if !compressedHeaders(httpVersion) {
+		headersSize = harRequestHeadersSize(request.Method, httpVersion, *requestURL, request.Headers)
+	}
 
 	return har.Request{
 		Method:      request.Method,
-		URL:         har.URL{*requestURL},
-		HTTPVersion: safeStringDereference(response.Protocol),
+		URL:         har.URL{URL: *requestURL},
+		HTTPVersion: httpVersion,
 		Cookies:     harCookies(request.Headers),
 		Headers:     harHeaders(request.Headers),
 		QueryString: harQueryStringParams(*requestURL),
-		// PostData: TODO,
+		PostData:    postData,
 		HeadersSize: headersSize,
 		BodySize:    bodySize,
 	}, nil
@@ -112,24 +209,34 @@ func harResponse(params *requestParams) (har.Response, error) {
 	redirectURL := &url.URL{}
 	if params.networkRequestWillBeSentRedirect != nil {
 		var err error
-		redirectResponse := params.networkRequestWillBeSentRedirect.RedirectResponse
-		redirectURL, err = url.Parse(redirectResponse.URL)
+		redirectRequest := params.networkRequestWillBeSentRedirect.Request
+		redirectURL, err = url.Parse(redirectRequest.URL)
 		if err != nil {
-			return har.Response{}, errors.Wrapf(err, "failed to parse url %q", redirectResponse.URL)
+			return har.Response{}, errors.Wrapf(err, "failed to parse url %q", redirectRequest.URL)
 		}
 	}
 
-	headersSize := harResponseHeadersSize(safeStringDereference(response.Protocol), response.Status, response.StatusText, response.Headers)
-	bodySize := params.networkLoadingFinished.EncodedDataLength - headersSize
+	httpVersion := harHTTPVersion(safeStringDereference(response.Protocol))
+	headersSize := -1
+	bodySize := params.networkLoadingFinished.EncodedDataLength
+	if !compressedHeaders(httpVersion) {
+		headersSize = harResponseHeadersSize(httpVersion, response.Status, response.StatusText, response.Headers)
+		// The headers size is an estimate, so it can exceed what was
+		// transferred, as it does for responses served from cache.
+		bodySize -= headersSize
+		if bodySize < 0 {
+			bodySize = 0
+		}
+	}
 
 	return har.Response{
 		Status:      response.Status,
 		StatusText:  response.StatusText,
-		HTTPVersion: safeStringDereference(response.Protocol),
+		HTTPVersion: httpVersion,
 		Cookies:     harCookies(response.Headers),
 		Headers:     harHeaders(response.Headers),
 		Content:     harContent(params, bodySize),
-		RedirectURL: har.URL{*redirectURL},
+		RedirectURL: har.URL{URL: *redirectURL},
 		HeadersSize: headersSize,
 		BodySize:    bodySize,
 	}, nil
@@ -139,27 +246,55 @@ func harCache(params *requestParams) (har.Cache, error) {
 	return har.Cache{}, nil
 }
 
+// harTimings splits the entry time into phases. Chrome's timing breakdown is
+// relative to requestTime, so the time before it and before the first phase
+// counts as blocked, and whatever follows the response headers counts as
+// receive, which keeps the phases summing to the entry time.
 func harTimings(params *requestParams) (har.Timings, error) {
 	response := params.networkResponseReceived.Response
+	total := math.Max(harEntryTime(params), 0)
 
+	notApplicable := -1.0
 	if response.Timing == nil {
-		return har.Timings{}, nil
+		return har.Timings{
+			Blocked: &notApplicable,
+			DNS:     &notApplicable,
+			Connect: &notApplicable,
+			SSL:     &notApplicable,
+			Receive: total,
+		}, nil
 	}
+	timing := response.Timing
 
-	correctTiming := func(num float64) float64 {
-		if num == 0.0 {
+	phase := func(start, end float64) float64 {
+		if start < 0 || end < start {
 			return -1
 		}
-		return num
+		return end - start
 	}
 
-	blocked := correctTiming(response.Timing.DNSStart)
-	dns := correctTiming(response.Timing.DNSEnd - response.Timing.DNSStart)
-	connect := correctTiming(response.Timing.ConnectEnd - response.Timing.ConnectStart)
-	send := correctTiming(response.Timing.SendEnd - response.Timing.SendStart)
-	wait := correctTiming(response.Timing.ReceiveHeadersEnd - response.Timing.SendEnd)
-	receive := params.networkLoadingFinished.Timestamp*1000 - params.networkRequestWillBeSent.Timestamp*1000 - response.Timing.ReceiveHeadersEnd
-	ssl := correctTiming(response.Timing.SSLEnd - response.Timing.SSLStart)
+	dns := phase(timing.DNSStart, timing.DNSEnd)
+	connect := phase(timing.ConnectStart, timing.ConnectEnd)
+	ssl := phase(timing.SSLStart, timing.SSLEnd)
+	send := math.Max(timing.SendEnd-timing.SendStart, 0)
+	wait := math.Max(timing.ReceiveHeadersEnd-timing.SendEnd, 0)
+
+	firstPhase := math.Max(timing.SendStart, 0)
+	for _, start := range []float64{timing.DNSStart, timing.ConnectStart} {
+		if start >= 0 && start < firstPhase {
+			firstPhase = start
+		}
+	}
+	queued := (timing.RequestTime - params.networkRequestWillBeSent.Timestamp) * 1000
+	blocked := math.Max(queued+firstPhase, 0)
+
+	receive := total - blocked - math.Max(dns, 0) - math.Max(connect, 0) - send - wait
+	if receive < 0 {
+		// The breakdown overran the recorded end, so blocked gives up the
+		// difference first.
+		blocked = math.Max(blocked+receive, 0)
+		receive = 0
+	}
 
 	return har.Timings{
 		Blocked: &blocked,
@@ -175,12 +310,14 @@ func harTimings(params *requestParams) (har.Timings, error) {
 func harCookies(headers map[string]string) []har.Cookie {
 	harCookies := make([]har.Cookie, 0)
 	for key, value := range headers {
-		if key == "Cookie" {
-			cookieStrs := strings.Split(value, ";")
-			for _, cookieStr := range cookieStrs {
-				harCookie := harCookie(cookieStr)
-				harCookies = append(harCookies, harCookie)
-			}
+		if isPseudoHeader(key) || !strings.EqualFold(key, "Cookie") {
+			continue
+		}
+
+		cookieStrs := strings.Split(value, ";")
+		for _, cookieStr := range cookieStrs {
+			harCookie := harCookie(cookieStr)
+			harCookies = append(harCookies, harCookie)
 		}
 	}
 	return harCookies
@@ -189,6 +326,9 @@ func harCookies(headers map[string]string) []har.Cookie {
 func harCookie(cookie string) har.Cookie {
 	cookie = strings.TrimSpace(cookie)
 	components := strings.SplitN(cookie, "=", 2)
+	if len(components) < 2 {
+		return har.Cookie{Name: components[0]}
+	}
 	return har.Cookie{Name: components[0], Value: components[1]}
 }
 
@@ -198,20 +338,33 @@ func harHeaders(headers map[string]string) []har.Header {
 		harHeader := har.Header{Name: key, Value: value}
 		harHeaders = append(harHeaders, harHeader)
 	}
+
+	sort.Slice(harHeaders, func(i, j int) bool {
+		iPseudo, jPseudo := isPseudoHeader(harHeaders[i].Name), isPseudoHeader(harHeaders[j].Name)
+		if iPseudo != jPseudo {
+			return iPseudo
+		}
+		return harHeaders[i].Name < harHeaders[j].Name
+	})
 	return harHeaders
 }
 
 func harRequestHeadersSize(method, protocol string, u url.URL, headers map[string]string) int {
 	size := len(fmt.Sprintf("%s %s %s\r\n", method, u.RequestURI(), protocol))
-	for key, value := range headers {
-		size += len(fmt.Sprintf("%s: %s\r\n", key, value))
-	}
-	return size + len("\r\n")
+	return size + harHeaderLinesSize(headers)
 }
 
 func harResponseHeadersSize(protocol string, statusCode int, statusText string, headers map[string]string) int {
 	size := len(fmt.Sprintf("%s %d %s\r\n", protocol, statusCode, statusText))
+	return size + harHeaderLinesSize(headers)
+}
+
+func harHeaderLinesSize(headers map[string]string) int {
+	size := 0
 	for key, value := range headers {
+		if isPseudoHeader(key) {
+			continue
+		}
 		size += len(fmt.Sprintf("%s: %s\r\n", key, value))
 	}
 	return size + len("\r\n")
@@ -228,6 +381,46 @@ func harQueryStringParams(u url.URL) []har.QueryStringParam {
 	return harQueryStringParams
 }
 
+// harPostData records the request body as sent. Form bodies also get their
+// params, in the order they were sent.
+func harPostData(headers map[string]string, text string) *har.PostData {
+	mimeType := ""
+	for key, value := range headers {
+		if strings.EqualFold(key, "Content-Type") {
+			mimeType = value
+			break
+		}
+	}
+
+	postData := &har.PostData{
+		MIMEType: mimeType,
+		Params:   make([]har.PostDataParam, 0),
+		Text:     text,
+	}
+	if !strings.Contains(strings.ToLower(mimeType), "application/x-www-form-urlencoded") {
+		return postData
+	}
+
+	for _, pair := range strings.Split(text, "&") {
+		if pair == "" {
+			continue
+		}
+		components := strings.SplitN(pair, "=", 2)
+		name, err := url.QueryUnescape(components[0])
+		if err != nil {
+			name = components[0]
+		}
+		value := ""
+		if len(components) == 2 {
+			if value, err = url.QueryUnescape(components[1]); err != nil {
+				value = components[1]
+			}
+		}
+		postData.Params = append(postData.Params, har.PostDataParam{Name: name, Value: &value})
+	}
+	return postData
+}
+
 func harContent(params *requestParams, bodySize int) har.Content {
 	response := params.networkResponseReceived.Response
 
@@ -242,7 +435,43 @@ func harContent(params *requestParams, bodySize int) har.Content {
 		Size:        size,
 		Compression: &compression,
 		MIMEType:    response.MimeType,
-		// Text: TODO,
-		// Encoding: TODO,
 	}
 }
+
+func harResponseBody(content *har.Content, requestID string, opts options) {
+	if body, ok := opts.responseBody(requestID); ok {
+		content.Text = &body.Body
+		if body.Base64Encoded {
+			encoding := encodingBase64
+			content.Encoding = &encoding
+		}
+	}
+}
+
+// ContentBody decodes the response body recorded in content, which is nil
+// when the body wasn't recorded.
+func ContentBody(content har.Content) ([]byte, error) {
+	if content.Text == nil {
+		return nil, nil
+	}
+	if content.Encoding != nil && *content.Encoding == encodingBase64 {
+		return base64.StdEncoding.DecodeString(*content.Text)
+	}
+	return []byte(*content.Text), nil
+}
+
+// ResponseHeader returns the recorded response headers that still apply to
+// the decoded body, without pseudo-headers and transfer headers.
+func ResponseHeader(response har.Response) http.Header {
+	header := make(http.Header)
+	for _, h := range response.Headers {
+		if isPseudoHeader(h.Name) || transferHeaders[strings.ToLower(h.Name)] {
+			continue
+		}
+		// Chrome joins repeated headers with newlines.
+		for _, value := range strings.Split(h.Value, "\n") {
+			header.Add(h.Name, value)
+		}
+	}
+	return header
+}
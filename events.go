@@ -0,0 +1,55 @@
+package chromedriver2har
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type Event struct {
+	Method    string
+	Params    json.RawMessage
+	Timestamp time.Time
+	TargetID  string
+	SessionID string
+}
+
+type EventSource interface {
+	Next() (Event, error)
+}
+
+func readEvents(source EventSource) ([]Event, error) {
+	events := make([]Event, 0)
+	for {
+		event, err := source.Next()
+		if err == io.EOF {
+			expanded, err := expandTraceEvents(events)
+			if err != nil {
+				return nil, err
+			}
+			return normalizeEvents(expanded), nil
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "failed to read event %d", len(events))
+		}
+		events = append(events, event)
+	}
+}
+
+type sliceSource struct {
+	events []Event
+}
+
+func NewSliceSource(events []Event) EventSource {
+	return &sliceSource{events: events}
+}
+
+func (s *sliceSource) Next() (Event, error) {
+	if len(s.events) == 0 {
+		return Event{}, io.EOF
+	}
+	event := s.events[0]
+	s.events = s.events[1:]
+	return event, nil
+}
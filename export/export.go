@@ -0,0 +1,232 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"net/url"
+	"strings"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/redact"
+	"github.com/pkg/errors"
+)
+
+type Format string
+
+const (
+	FormatCurl Format = "curl"
+	FormatGo   Format = "go"
+	FormatHTTP Format = "http"
+)
+
+// Headers the client computes itself when sending the exported request.
+var skippedHeaders = map[string]bool{
+	"connection":     true,
+	"content-length": true,
+}
+
+type Options struct {
+	Redactor *redact.Redactor
+}
+
+type request struct {
+	method  string
+	url     string
+	target  string
+	host    string
+	headers []har.Header
+	body    string
+}
+
+func Export(entry har.Entry, f Format, opts Options) (string, error) {
+	switch f {
+	case FormatCurl:
+		return Curl(entry, opts), nil
+	case FormatGo:
+		return Go(entry, opts)
+	case FormatHTTP:
+		return HTTP(entry, opts), nil
+	default:
+		return "", errors.Errorf("unknown format %q", f)
+	}
+}
+
+func Curl(entry har.Entry, opts Options) string {
+	r := newRequest(entry, opts)
+
+	// curl sends a GET, or a POST when it has data to send.
+	implicitMethod := "GET"
+	if r.body != "" {
+		implicitMethod = "POST"
+	}
+
+	args := []string{"curl " + shellQuote(r.url)}
+	switch {
+	case r.method == "HEAD":
+		// -X HEAD makes curl wait for a body that never comes.
+		args = append(args, "--head")
+	case r.method != implicitMethod:
+		args = append(args, "-X "+r.method)
+	}
+
+	compressed := false
+	for _, header := range r.headers {
+		args = append(args, "-H "+shellQuote(header.Name+": "+header.Value))
+		if strings.EqualFold(header.Name, "Accept-Encoding") {
+			compressed = true
+		}
+	}
+
+	if r.body != "" {
+		args = append(args, "--data-raw "+shellQuote(r.body))
+	}
+	if compressed {
+		args = append(args, "--compressed")
+	}
+
+	return strings.Join(args, " \\\n  ")
+}
+
+func Go(entry har.Entry, opts Options) (string, error) {
+	r := newRequest(entry, opts)
+
+	var buf bytes.Buffer
+	buf.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"io/ioutil\"\n\t\"log\"\n\t\"net/http\"\n")
+	if r.body != "" {
+		buf.WriteString("\t\"strings\"\n")
+	}
+	buf.WriteString(")\n\nfunc main() {\n")
+
+	if r.body != "" {
+		fmt.Fprintf(&buf, "body := strings.NewReader(%q)\n", r.body)
+		fmt.Fprintf(&buf, "req, err := http.NewRequest(%q, %q, body)\n", r.method, r.url)
+	} else {
+		fmt.Fprintf(&buf, "req, err := http.NewRequest(%q, %q, nil)\n", r.method, r.url)
+	}
+	buf.WriteString("if err != nil {\nlog.Fatal(err)\n}\n")
+
+	for _, header := range r.headers {
+		if strings.EqualFold(header.Name, "Host") {
+			fmt.Fprintf(&buf, "req.Host = %q\n", header.Value)
+			continue
+		}
+		// Setting Accept-Encoding turns off the transport's transparent
+		// gzip decoding, so the response would print compressed.
+		if strings.EqualFold(header.Name, "Accept-Encoding") {
+			continue
+		}
+		fmt.Fprintf(&buf, "req.Header.Add(%q, %q)\n", header.Name, header.Value)
+	}
+
+	buf.WriteString("\nresp, err := http.DefaultClient.Do(req)\nif err != nil {\nlog.Fatal(err)\n}\ndefer resp.Body.Close()\n\n")
+	buf.WriteString("data, err := ioutil.ReadAll(resp.Body)\nif err != nil {\nlog.Fatal(err)\n}\nfmt.Println(resp.Status)\nfmt.Println(string(data))\n}\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", errors.Wrap(err, "failed to format generated source")
+	}
+	return string(source), nil
+}
+
+func HTTP(entry har.Entry, opts Options) string {
+	r := newRequest(entry, opts)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s HTTP/1.1\r\n", r.method, r.target)
+
+	hasHost := false
+	for _, header := range r.headers {
+		hasHost = hasHost || strings.EqualFold(header.Name, "Host")
+	}
+	if !hasHost {
+		fmt.Fprintf(&buf, "Host: %s\r\n", r.host)
+	}
+
+	for _, header := range r.headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", header.Name, header.Value)
+	}
+	if r.body != "" {
+		fmt.Fprintf(&buf, "Content-Length: %d\r\n", len(r.body))
+	}
+	buf.WriteString("\r\n")
+	buf.WriteString(r.body)
+	return buf.String()
+}
+
+func newRequest(entry har.Entry, opts Options) request {
+	harRequest := entry.Request
+	if opts.Redactor != nil {
+		harRequest = redactedRequest(opts.Redactor, harRequest)
+	}
+
+	u := harRequest.URL.URL
+	r := request{
+		method: harRequest.Method,
+		url:    u.String(),
+		target: u.RequestURI(),
+		host:   u.Host,
+		body:   postBody(harRequest.PostData),
+	}
+
+	hasCookie := false
+	for _, header := range harRequest.Headers {
+		name := strings.ToLower(header.Name)
+		switch {
+		case name == ":authority":
+			// HTTP/2 carries the host as a pseudo-header.
+			r.host = header.Value
+		case strings.HasPrefix(name, ":") || skippedHeaders[name]:
+		default:
+			hasCookie = hasCookie || name == "cookie"
+			r.headers = append(r.headers, header)
+		}
+	}
+
+	if !hasCookie && len(harRequest.Cookies) > 0 {
+		cookies := make([]string, 0, len(harRequest.Cookies))
+		for _, cookie := range harRequest.Cookies {
+			cookies = append(cookies, cookie.Name+"="+cookie.Value)
+		}
+		r.headers = append(r.headers, har.Header{Name: "Cookie", Value: strings.Join(cookies, "; ")})
+	}
+
+	return r
+}
+
+func postBody(postData *har.PostData) string {
+	if postData == nil {
+		return ""
+	}
+	if postData.Text != "" || len(postData.Params) == 0 {
+		return postData.Text
+	}
+
+	values := make(url.Values)
+	for _, param := range postData.Params {
+		if param.Value != nil {
+			values.Add(param.Name, *param.Value)
+		}
+	}
+	return values.Encode()
+}
+
+// redactedRequest redacts a copy of the request, leaving the entry untouched.
+func redactedRequest(redactor *redact.Redactor, r har.Request) har.Request {
+	r.Headers = append([]har.Header(nil), r.Headers...)
+	r.Cookies = append([]har.Cookie(nil), r.Cookies...)
+	r.QueryString = append([]har.QueryStringParam(nil), r.QueryString...)
+	if r.PostData != nil {
+		postData := *r.PostData
+		postData.Params = append([]har.PostDataParam(nil), postData.Params...)
+		r.PostData = &postData
+	}
+
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{{Request: r}}}}
+	redactor.Redact(h)
+	return h.Log.Entries[0].Request
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
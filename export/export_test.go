@@ -0,0 +1,112 @@
+package export
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/internal/hartest"
+	"github.com/jordanpotter/chromedriver2har/redact"
+	"github.com/stretchr/testify/require"
+)
+
+func postEntry() har.Entry {
+	return hartest.NewEntry("POST", "https://example.com/api/login?next=/home").
+		RequestHeader(":authority", "example.com").
+		RequestHeader(":method", "POST").
+		RequestHeader("content-type", "application/json").
+		RequestHeader("accept-encoding", "gzip").
+		RequestHeader("content-length", "24").
+		RequestCookie("session", "s1").
+		PostData("application/json", `{"user":"o'brien"}`).
+		Build()
+}
+
+func TestCurl(t *testing.T) {
+	require.Equal(t, `curl 'https://example.com/api/login?next=/home' \
+  -H 'content-type: application/json' \
+  -H 'accept-encoding: gzip' \
+  -H 'Cookie: session=s1' \
+  --data-raw '{"user":"o'\''brien"}' \
+  --compressed`, Curl(postEntry(), Options{}))
+
+	deleteEntry := hartest.NewEntry("DELETE", "https://example.com/items/1").Build()
+	require.Equal(t, "curl 'https://example.com/items/1' \\\n  -X DELETE", Curl(deleteEntry, Options{}))
+
+	headEntry := hartest.NewEntry("HEAD", "https://example.com/items/1").Build()
+	require.Equal(t, "curl 'https://example.com/items/1' \\\n  --head", Curl(headEntry, Options{}))
+
+	searchEntry := hartest.NewEntry("GET", "https://example.com/search").PostData("application/json", `{"q":1}`).Build()
+	require.Equal(t, "curl 'https://example.com/search' \\\n  -X GET \\\n  --data-raw '{\"q\":1}'", Curl(searchEntry, Options{}))
+}
+
+func TestCurlFromEvents(t *testing.T) {
+	event := func(method, params string) chromedriver2har.Event {
+		return chromedriver2har.Event{Method: method, Params: json.RawMessage(params)}
+	}
+	events := []chromedriver2har.Event{
+		event(chromedriver2har.MethodNetworkRequestWillBeSent, `{"requestId":"1","documentURL":"https://example.com/","request":{"url":"https://example.com/login","method":"POST","headers":{"Content-Type":"application/x-www-form-urlencoded"},"postData":"user=o%27brien&next=%2Fhome"},"timestamp":10,"wallTime":1760695200}`),
+		event(chromedriver2har.MethodNetworkResponseReceived, `{"requestId":"1","timestamp":10.01,"response":{"url":"https://example.com/login","status":200,"headers":{},"mimeType":"text/html"}}`),
+		event(chromedriver2har.MethodNetworkLoadingFinished, `{"requestId":"1","timestamp":10.02,"encodedDataLength":100}`),
+	}
+
+	h, err := chromedriver2har.FromEventSource(chromedriver2har.NewSliceSource(events))
+	require.NoError(t, err)
+	require.Len(t, h.Log.Entries, 1)
+
+	postData := h.Log.Entries[0].Request.PostData
+	require.NotNil(t, postData)
+	require.Equal(t, "application/x-www-form-urlencoded", postData.MIMEType)
+	require.Len(t, postData.Params, 2)
+	require.Equal(t, "user", postData.Params[0].Name)
+	require.Equal(t, "o'brien", *postData.Params[0].Value)
+	require.Equal(t, "next", postData.Params[1].Name)
+	require.Equal(t, "/home", *postData.Params[1].Value)
+
+	require.Equal(t, `curl 'https://example.com/login' \
+  -H 'Content-Type: application/x-www-form-urlencoded' \
+  --data-raw 'user=o%27brien&next=%2Fhome'`, Curl(h.Log.Entries[0], Options{}))
+}
+
+func TestHTTP(t *testing.T) {
+	require.Equal(t, "POST /api/login?next=/home HTTP/1.1\r\n"+
+		"Host: example.com\r\n"+
+		"content-type: application/json\r\n"+
+		"accept-encoding: gzip\r\n"+
+		"Cookie: session=s1\r\n"+
+		"Content-Length: 18\r\n"+
+		"\r\n"+
+		`{"user":"o'brien"}`, HTTP(postEntry(), Options{}))
+}
+
+func TestGo(t *testing.T) {
+	source, err := Go(postEntry(), Options{})
+	require.NoError(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "main.go", source, 0)
+	require.NoError(t, err)
+	require.Contains(t, source, "body := strings.NewReader(\"{\\\"user\\\":\\\"o'brien\\\"}\")")
+	require.Contains(t, source, `req, err := http.NewRequest("POST", "https://example.com/api/login?next=/home", body)`)
+	require.Contains(t, source, `req.Header.Add("Cookie", "session=s1")`)
+	require.NotContains(t, source, "accept-encoding")
+}
+
+func TestRedaction(t *testing.T) {
+	redactor, err := redact.New(redact.Config{Cookies: []string{"session"}, BodyPaths: []string{"user"}})
+	require.NoError(t, err)
+
+	entry := postEntry()
+	exported, err := Export(entry, FormatHTTP, Options{Redactor: redactor})
+	require.NoError(t, err)
+	require.Contains(t, exported, "Cookie: session="+redactor.Placeholder("s1"))
+	require.Contains(t, exported, `{"user":"`+redactor.Placeholder("o'brien")+`"}`)
+
+	require.Equal(t, "s1", entry.Request.Cookies[0].Value)
+	require.Equal(t, `{"user":"o'brien"}`, entry.Request.PostData.Text)
+
+	_, err = Export(entry, "wget", Options{})
+	require.Error(t, err)
+}
@@ -0,0 +1,245 @@
+package filter
+
+import (
+	"mime"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/pkg/errors"
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	PartyFirst = "first"
+	PartyThird = "third"
+)
+
+type Filter struct {
+	URLGlobs      []string
+	URLRegexps    []string
+	Hosts         []string
+	ResourceTypes []string
+	MIMETypes     []string
+	StatusClasses []string
+	After         time.Time
+	Before        time.Time
+	Pages         []string
+	Party         string
+}
+
+type Matcher struct {
+	urlPatterns   []*regexp.Regexp
+	hosts         []string
+	resourceTypes map[string]bool
+	mimeTypes     []string
+	statusClasses map[int]bool
+	after         time.Time
+	before        time.Time
+	pages         map[string]bool
+	party         string
+}
+
+func (f Filter) Compile() (*Matcher, error) {
+	m := &Matcher{
+		hosts:         lower(f.Hosts),
+		resourceTypes: lowerSet(f.ResourceTypes),
+		mimeTypes:     lower(f.MIMETypes),
+		statusClasses: make(map[int]bool, len(f.StatusClasses)),
+		after:         f.After,
+		before:        f.Before,
+		pages:         make(map[string]bool, len(f.Pages)),
+		party:         f.Party,
+	}
+
+	for _, glob := range f.URLGlobs {
+		re, err := regexp.Compile(globPattern(glob))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compile glob %q", glob)
+		}
+		m.urlPatterns = append(m.urlPatterns, re)
+	}
+
+	for _, pattern := range f.URLRegexps {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compile regexp %q", pattern)
+		}
+		m.urlPatterns = append(m.urlPatterns, re)
+	}
+
+	for _, statusClass := range f.StatusClasses {
+		class, err := parseStatusClass(statusClass)
+		if err != nil {
+			return nil, err
+		}
+		m.statusClasses[class] = true
+	}
+
+	for _, page := range f.Pages {
+		m.pages[page] = true
+	}
+
+	if f.Party != "" && f.Party != PartyFirst && f.Party != PartyThird {
+		return nil, errors.Errorf("unknown party %q", f.Party)
+	}
+
+	return m, nil
+}
+
+func (m *Matcher) Match(entry har.Entry) bool {
+	u := entry.Request.URL.String()
+
+	if len(m.urlPatterns) > 0 && !anyMatch(m.urlPatterns, u) {
+		return false
+	}
+
+	if len(m.hosts) > 0 && !hostMatches(m.hosts, entry.Request.URL.Hostname()) {
+		return false
+	}
+
+	if len(m.resourceTypes) > 0 && !m.resourceTypes[strings.ToLower(entry.Custom.String(chromedriver2har.CustomResourceType))] {
+		return false
+	}
+
+	if len(m.mimeTypes) > 0 && !mimeMatches(m.mimeTypes, entry.Response.Content.MIMEType) {
+		return false
+	}
+
+	if len(m.statusClasses) > 0 && !m.statusClasses[entry.Response.Status/100] {
+		return false
+	}
+
+	if !m.after.IsZero() && entry.StartedDateTime.Before(m.after) {
+		return false
+	}
+
+	if !m.before.IsZero() && entry.StartedDateTime.After(m.before) {
+		return false
+	}
+
+	if len(m.pages) > 0 && (entry.PageRef == nil || !m.pages[*entry.PageRef]) {
+		return false
+	}
+
+	if m.party != "" && (m.party == PartyThird) != ThirdParty(entry) {
+		return false
+	}
+
+	return true
+}
+
+func (m *Matcher) Option() chromedriver2har.Option {
+	return chromedriver2har.WithEntryFilter(m.Match)
+}
+
+func Apply(h *har.HAR, f Filter) (*har.HAR, error) {
+	m, err := f.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	pages, entries := har.FilterEntries(h.Log.Pages, h.Log.Entries, m.Match)
+
+	filtered := *h
+	filtered.Log.Pages = pages
+	filtered.Log.Entries = entries
+	return &filtered, nil
+}
+
+func ThirdParty(entry har.Entry) bool {
+	documentURL, err := url.Parse(entry.Custom.String(chromedriver2har.CustomDocumentURL))
+	if err != nil || documentURL.Hostname() == "" || entry.Request.URL.Hostname() == "" {
+		return false
+	}
+	return registrableDomain(documentURL.Hostname()) != registrableDomain(entry.Request.URL.Hostname())
+}
+
+func registrableDomain(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		// IP addresses, localhost and bare public suffixes are their own site.
+		return host
+	}
+	return domain
+}
+
+func globPattern(glob string) string {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			pattern.WriteString(".*")
+		case '?':
+			pattern.WriteString(".")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	pattern.WriteString("$")
+	return pattern.String()
+}
+
+func parseStatusClass(statusClass string) (int, error) {
+	trimmed := strings.TrimSuffix(strings.ToLower(statusClass), "xx")
+	class, err := strconv.Atoi(trimmed)
+	if err != nil || class < 1 || class > 5 {
+		return 0, errors.Errorf("invalid status class %q", statusClass)
+	}
+	return class, nil
+}
+
+func anyMatch(patterns []*regexp.Regexp, s string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func hostMatches(hosts []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, h := range hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+func mimeMatches(mimeTypes []string, mimeType string) bool {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		mediaType = strings.ToLower(mimeType)
+	}
+	for _, m := range mimeTypes {
+		prefix := strings.TrimSuffix(m, "*")
+		if mediaType == m || (strings.HasSuffix(prefix, "/") && strings.HasPrefix(mediaType, prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+func lower(values []string) []string {
+	lowered := make([]string, 0, len(values))
+	for _, value := range values {
+		lowered = append(lowered, strings.ToLower(value))
+	}
+	return lowered
+}
+
+func lowerSet(values []string) map[string]bool {
+	s := make(map[string]bool, len(values))
+	for _, value := range values {
+		s[strings.ToLower(value)] = true
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/internal/hartest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	start := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	entry := func(rawURL string) *hartest.Entry {
+		return hartest.NewEntry("GET", rawURL).Custom(chromedriver2har.CustomDocumentURL, "https://www.example.com/")
+	}
+	resourceType := chromedriver2har.CustomResourceType
+
+	h := &har.HAR{Log: har.Log{
+		Pages: []har.Page{{ID: "page_1"}, {ID: "page_2"}},
+		Entries: []har.Entry{
+			entry("https://www.example.com/").Page("page_1").Started(start).Custom(resourceType, "Document").Status(200).MIMEType("text/html; charset=utf-8").Build(),
+			entry("https://api.example.com/v1/users").Page("page_1").Started(start.Add(time.Second)).Custom(resourceType, "XHR").Status(200).MIMEType("application/json").Build(),
+			entry("https://cdn.tracker.net/pixel.gif").Page("page_1").Started(start.Add(2*time.Second)).Custom(resourceType, "Image").Status(404).MIMEType("image/gif").Build(),
+			entry("https://api.example.com/v1/orders").Page("page_2").Started(start.Add(3*time.Second)).Custom(resourceType, "Fetch").Status(500).MIMEType("application/json").Build(),
+		},
+	}}
+
+	urls := func(h *har.HAR) []string {
+		urls := make([]string, 0, len(h.Log.Entries))
+		for _, entry := range h.Log.Entries {
+			urls = append(urls, entry.Request.URL.String())
+		}
+		return urls
+	}
+
+	filtered, err := Apply(h, Filter{URLGlobs: []string{"https://api.example.com/*"}, StatusClasses: []string{"2xx"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://api.example.com/v1/users"}, urls(filtered))
+	require.Equal(t, []har.Page{{ID: "page_1"}}, filtered.Log.Pages)
+
+	filtered, err = Apply(h, Filter{Party: PartyThird})
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://cdn.tracker.net/pixel.gif"}, urls(filtered))
+
+	filtered, err = Apply(h, Filter{MIMETypes: []string{"text/*", "image/"}, Before: start.Add(time.Second)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://www.example.com/"}, urls(filtered))
+
+	filtered, err = Apply(h, Filter{ResourceTypes: []string{"xhr", "fetch"}, Pages: []string{"page_2"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://api.example.com/v1/orders"}, urls(filtered))
+	require.Len(t, h.Log.Entries, 4)
+
+	_, err = Apply(h, Filter{StatusClasses: []string{"9xx"}})
+	require.Error(t, err)
+}
+
+func TestRegistrableDomain(t *testing.T) {
+	for host, expected := range map[string]string{
+		"www.example.com":  "example.com",
+		"a.example.co.uk":  "example.co.uk",
+		"b.co.uk":          "b.co.uk",
+		"user.github.io":   "user.github.io",
+		"localhost":        "localhost",
+		"127.0.0.1":        "127.0.0.1",
+		"API.Example.COM.": "example.com",
+	} {
+		require.Equal(t, expected, registrableDomain(host), host)
+	}
+	require.NotEqual(t, registrableDomain("a.co.uk"), registrableDomain("b.co.uk"))
+}
@@ -1,10 +1,10 @@
 package chromedriver2har
 
 import (
-	"encoding/json"
+	"fmt"
 
 	"github.com/fedesog/webdriver"
-	"github.com/jordanpotter/har"
+	"github.com/jordanpotter/chromedriver2har/har"
 	"github.com/pkg/errors"
 )
 
@@ -14,43 +14,105 @@ const (
 	creatorVersion = "0.1"
 )
 
-func New(logEntries []webdriver.LogEntry) (*har.HAR, error) {
-	chromeLogEntries, err := chromeLogEntries(logEntries)
+func New(logEntries []webdriver.LogEntry, opts ...Option) (*har.HAR, error) {
+	return FromEventSource(NewLogEntrySource(logEntries), opts...)
+}
+
+func FromEventSource(source EventSource, opts ...Option) (*har.HAR, error) {
+	events, err := readEvents(source)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create chrome log entries")
+		return nil, errors.Wrap(err, "failed to read events")
 	}
 
-	page, err := harPage(chromeLogEntries)
+	return harFromTargets(eventsByTarget(events), targetTypes(events), newOptions(opts))
+}
+
+func FromEventSourceByTarget(source EventSource, opts ...Option) (map[string]*har.HAR, error) {
+	events, err := readEvents(source)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create HAR page")
+		return nil, errors.Wrap(err, "failed to read events")
 	}
 
-	entries, err := harEntries(chromeLogEntries)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create HAR entries")
+	targets := eventsByTarget(events)
+	targetTypes := targetTypes(events)
+	harsByTarget := make(map[string]*har.HAR, len(targets))
+	for _, target := range targets {
+		if !target.hasRequests() {
+			continue
+		}
+
+		h, err := harFromTargets([]targetEvents{target}, targetTypes, newOptions(opts))
+		if err != nil {
+			return nil, err
+		}
+		harsByTarget[target.targetID] = h
 	}
 
-	return &har.HAR{
+	return harsByTarget, nil
+}
+
+func harFromTargets(targets []targetEvents, targetTypes map[string]string, opts options) (*har.HAR, error) {
+	pages := make([]har.Page, 0, len(targets))
+	entries := make([]har.Entry, 0)
+	serviceWorkerEntries := make([]har.Entry, 0)
+	pageCount := 0
+
+	for _, target := range targets {
+		if !target.hasRequests() {
+			continue
+		}
+
+		if targetTypes[target.targetID] == targetTypeServiceWorker {
+			targetEntries, err := harEntries("", InitiatorServiceWorker, target.events, opts)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to create HAR entries for service worker %q", target.targetID)
+			}
+			serviceWorkerEntries = append(serviceWorkerEntries, targetEntries...)
+			continue
+		}
+
+		// Page IDs count every page, including those whose entries are all
+		// filtered out, so they don't depend on the filters.
+		pageCount++
+		pageID := fmt.Sprintf("page_%d", pageCount)
+
+		page, err := harPage(pageID, target.events)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create HAR page for target %q", target.targetID)
+		}
+
+		targetEntries, err := harEntries(pageID, "", target.events, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create HAR entries for target %q", target.targetID)
+		}
+		if len(targetEntries) == 0 && opts.filtered() {
+			continue
+		}
+
+		pages = append(pages, page)
+		entries = append(entries, targetEntries...)
+	}
+
+	correlateServiceWorkerEntries(entries, serviceWorkerEntries)
+	entries = append(entries, serviceWorkerEntries...)
+
+	entries = har.SortedByStart(entries)
+
+	h := &har.HAR{
 		Log: har.Log{
 			Version: harVersion,
 			Creator: har.Creator{
 				Name:    creatorName,
 				Version: creatorVersion,
 			},
-			Pages:   []har.Page{page},
+			Pages:   pages,
 			Entries: entries,
 		},
-	}, nil
-}
+	}
 
-func chromeLogEntries(logEntries []webdriver.LogEntry) ([]ChromeLogEntry, error) {
-	chromeLogEntries := make([]ChromeLogEntry, 0, len(logEntries))
-	for _, logEntry := range logEntries {
-		var chromeEntry ChromeLogEntry
-		if err := json.Unmarshal([]byte(logEntry.Message), &chromeEntry); err != nil {
-			return nil, errors.Wrapf(err, "failed to unmarshal log entry at timestamp %d", logEntry.TimeStamp)
-		}
-		chromeLogEntries = append(chromeLogEntries, chromeEntry)
+	if opts.redactor != nil {
+		opts.redactor.Redact(h)
 	}
-	return chromeLogEntries, nil
+
+	return h, nil
 }
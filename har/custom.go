@@ -0,0 +1,120 @@
+package har
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+type Custom map[string]interface{}
+
+func (c Custom) Decode(key string, v interface{}) (bool, error) {
+	value, ok := c[key]
+	if !ok {
+		return false, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return true, err
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+func (c Custom) String(key string) string {
+	var s string
+	if ok, err := c.Decode(key, &s); !ok || err != nil {
+		return ""
+	}
+	return s
+}
+
+func marshalWithCustom(v interface{}, custom Custom) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(custom) == 0 {
+		return data, err
+	}
+
+	keys := make([]string, 0, len(custom))
+	for key := range custom {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, key := range keys {
+		keyData, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueData, err := json.Marshal(custom[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteByte(',')
+		buf.Write(keyData)
+		buf.WriteByte(':')
+		buf.Write(valueData)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func unmarshalCustom(data []byte) (Custom, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	var custom Custom
+	for key, value := range fields {
+		if !strings.HasPrefix(key, "_") {
+			continue
+		}
+		if custom == nil {
+			custom = make(Custom)
+		}
+		custom[key] = value
+	}
+	return custom, nil
+}
+
+type page Page
+
+func (p Page) MarshalJSON() ([]byte, error) {
+	return marshalWithCustom(page(p), p.Custom)
+}
+
+func (p *Page) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*page)(p)); err != nil {
+		return err
+	}
+
+	custom, err := unmarshalCustom(data)
+	if err != nil {
+		return err
+	}
+	p.Custom = custom
+	return nil
+}
+
+type entry Entry
+
+func (e Entry) MarshalJSON() ([]byte, error) {
+	return marshalWithCustom(entry(e), e.Custom)
+}
+
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*entry)(e)); err != nil {
+		return err
+	}
+
+	custom, err := unmarshalCustom(data)
+	if err != nil {
+		return err
+	}
+	e.Custom = custom
+	return nil
+}
@@ -0,0 +1,145 @@
+// Package har extends the github.com/jordanpotter/har model with the custom
+// underscore-prefixed fields chromedriver2har records on pages and entries,
+// millisecond timestamps and URLs that decode as well as encode. Types that
+// need no extra behavior are aliases of the upstream types.
+package har
+
+import (
+	"net"
+	"sort"
+
+	upstream "github.com/jordanpotter/har"
+)
+
+type (
+	Creator          = upstream.Creator
+	Browser          = upstream.Browser
+	PageTimings      = upstream.PageTimings
+	Header           = upstream.Header
+	QueryStringParam = upstream.QueryStringParam
+	PostData         = upstream.PostData
+	PostDataParam    = upstream.PostDataParam
+	Content          = upstream.Content
+	Timings          = upstream.Timings
+)
+
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+type Log struct {
+	Version string   `json:"version"`
+	Creator Creator  `json:"creator"`
+	Browser *Browser `json:"browser,omitempty"`
+	Pages   []Page   `json:"pages,omitempty"`
+	Entries []Entry  `json:"entries"`
+	Comment *string  `json:"comment,omitempty"`
+}
+
+type Page struct {
+	StartedDateTime Time        `json:"startedDateTime"`
+	ID              string      `json:"id"`
+	Title           string      `json:"title"`
+	PageTimings     PageTimings `json:"pageTimings"`
+	Comment         *string     `json:"comment,omitempty"`
+	Custom          Custom      `json:"-"`
+}
+
+type Entry struct {
+	PageRef         *string  `json:"pageref,omitempty"`
+	StartedDateTime Time     `json:"startedDateTime"`
+	Time            float64  `json:"time"`
+	Request         Request  `json:"request"`
+	Response        Response `json:"response"`
+	Cache           Cache    `json:"cache"`
+	Timings         Timings  `json:"timings"`
+	ServerIPAddress *net.IP  `json:"serverIPAddress,omitempty"`
+	Connection      *string  `json:"connection,omitempty"`
+	Comment         *string  `json:"comment,omitempty"`
+	Custom          Custom   `json:"-"`
+}
+
+type Request struct {
+	Method      string             `json:"method"`
+	URL         URL                `json:"url"`
+	HTTPVersion string             `json:"httpVersion"`
+	Cookies     []Cookie           `json:"cookies"`
+	Headers     []Header           `json:"headers"`
+	QueryString []QueryStringParam `json:"queryString"`
+	PostData    *PostData          `json:"postData,omitempty"`
+	HeadersSize int                `json:"headersSize"`
+	BodySize    int                `json:"bodySize"`
+	Comment     *string            `json:"comment,omitempty"`
+}
+
+type Response struct {
+	Status      int      `json:"status"`
+	StatusText  string   `json:"statusText"`
+	HTTPVersion string   `json:"httpVersion"`
+	Cookies     []Cookie `json:"cookies"`
+	Headers     []Header `json:"headers"`
+	Content     Content  `json:"content"`
+	RedirectURL URL      `json:"redirectURL"`
+	HeadersSize int      `json:"headersSize"`
+	BodySize    int      `json:"bodySize"`
+	Comment     *string  `json:"comment,omitempty"`
+}
+
+type Cookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Path     *string `json:"path,omitempty"`
+	Domain   *string `json:"domain,omitempty"`
+	Expires  *Time   `json:"expires,omitempty"`
+	HTTPOnly *bool   `json:"httpOnly,omitempty"`
+	Secure   *bool   `json:"secure,omitempty"`
+	Comment  *string `json:"comment,omitempty"`
+}
+
+type Cache struct {
+	BeforeRequest *CacheRequest `json:"beforeRequest,omitempty"`
+	AfterRequest  *CacheRequest `json:"afterRequest,omitempty"`
+	Comment       *string       `json:"comment,omitempty"`
+}
+
+type CacheRequest struct {
+	Expires    *Time   `json:"expires,omitempty"`
+	LastAccess Time    `json:"lastAccess"`
+	ETag       string  `json:"eTag"`
+	HitCount   int     `json:"hitCount"`
+	Comment    *string `json:"comment,omitempty"`
+}
+
+// FilterEntries returns the entries for which keep returns true, along with
+// the pages that those entries still reference.
+func FilterEntries(pages []Page, entries []Entry, keep func(Entry) bool) ([]Page, []Entry) {
+	keptEntries := make([]Entry, 0, len(entries))
+	referencedPages := make(map[string]bool, len(pages))
+	for _, entry := range entries {
+		if !keep(entry) {
+			continue
+		}
+		keptEntries = append(keptEntries, entry)
+		if entry.PageRef != nil {
+			referencedPages[*entry.PageRef] = true
+		}
+	}
+
+	keptPages := make([]Page, 0, len(pages))
+	for _, page := range pages {
+		if referencedPages[page.ID] {
+			keptPages = append(keptPages, page)
+		}
+	}
+
+	return keptPages, keptEntries
+}
+
+// SortedByStart returns a copy of entries in the order they started.
+func SortedByStart(entries []Entry) []Entry {
+	sorted := append([]Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartedDateTime.Before(sorted[j].StartedDateTime.Time)
+	})
+	return sorted
+}
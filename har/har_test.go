@@ -0,0 +1,63 @@
+package har
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomFields(t *testing.T) {
+	entry := Entry{Custom: Custom{"_requestId": "1.1", "_pushed": true}}
+	data, err := json.Marshal(entry)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `,"_pushed":true,"_requestId":"1.1"}`)
+
+	var decoded Entry
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, "1.1", decoded.Custom.String("_requestId"))
+
+	var pushed bool
+	ok, err := decoded.Custom.Decode("_pushed", &pushed)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, pushed)
+
+	page := Page{ID: "page_1", Custom: Custom{"_firstPaint": 12.5}}
+	data, err = json.Marshal(page)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"id":"page_1"`)
+	require.Contains(t, string(data), `"_firstPaint":12.5}`)
+
+	var decodedPage Page
+	require.NoError(t, json.Unmarshal(data, &decodedPage))
+	require.Len(t, decodedPage.Custom, 1)
+
+	data, err = json.Marshal(Page{ID: "page_2"})
+	require.NoError(t, err)
+	require.NotContains(t, string(data), `"_`)
+}
+
+func TestTimeFormat(t *testing.T) {
+	data, err := json.Marshal(Time{Time: time.Date(2017, 1, 9, 2, 53, 8, 654120000, time.UTC)})
+	require.NoError(t, err)
+	require.Equal(t, `"2017-01-09T02:53:08.654+00:00"`, string(data))
+
+	var decoded Time
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, time.Date(2017, 1, 9, 2, 53, 8, 654000000, time.UTC), decoded.UTC())
+}
+
+func TestURL(t *testing.T) {
+	var request Request
+	require.NoError(t, json.Unmarshal([]byte(`{"method":"GET","url":"https://example.com/a?b=1"}`), &request))
+	require.Equal(t, "example.com", request.URL.Host)
+	require.Equal(t, "1", request.URL.Query().Get("b"))
+
+	data, err := json.Marshal(request.URL)
+	require.NoError(t, err)
+	require.Equal(t, `"https://example.com/a?b=1"`, string(data))
+
+	require.Error(t, json.Unmarshal([]byte(`"%zz"`), &request.URL))
+}
@@ -0,0 +1,14 @@
+package har
+
+import "time"
+
+// TimeFormat is ISO 8601 with milliseconds, as the HAR 1.2 spec shows.
+const TimeFormat = "2006-01-02T15:04:05.000-07:00"
+
+type Time struct {
+	time.Time
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + t.Format(TimeFormat) + `"`), nil
+}
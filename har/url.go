@@ -0,0 +1,27 @@
+package har
+
+import (
+	"encoding/json"
+	"net/url"
+)
+
+type URL struct {
+	url.URL
+}
+
+func (u URL) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + u.String() + `"`), nil
+}
+
+func (u *URL) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	u.URL = *parsed
+	return nil
+}
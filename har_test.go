@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/fedesog/webdriver"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/validate"
 	"github.com/stretchr/testify/require"
 )
 
@@ -121,6 +123,54 @@ var logEntries = []webdriver.LogEntry{
 	{Level: "INFO", TimeStamp: int(time.Now().Unix() * 1000), Message: `{"message":{"method":"Network.loadingFinished","params":{"encodedDataLength":40,"requestId":"14990.34","timestamp":11872.922878}},"webview":"e7d25798-dcf1-410b-a9c7-1fadc88b952c"}`},
 }
 
+func TestStartedDateTimeFormat(t *testing.T) {
+	h, err := New(logEntries)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(h.Log.Pages[0])
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"startedDateTime":"2017-01-09T02:53:08.654+00:00"`)
+
+	data, err = json.Marshal(h.Log.Entries[0])
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"startedDateTime":"2017-01-09T02:53:08.654+00:00"`)
+}
+
+func TestNewValidates(t *testing.T) {
+	h, err := New(logEntries)
+	require.NoError(t, err)
+
+	findings := validate.Validate(h)
+	require.False(t, validate.HasErrors(findings), "%v", findings)
+}
+
+type recordingBodies struct {
+	requestIDs []string
+}
+
+func (b *recordingBodies) ResponseBody(requestID string) (NetworkGetResponseBody, bool) {
+	b.requestIDs = append(b.requestIDs, requestID)
+	return NetworkGetResponseBody{Body: "body"}, true
+}
+
+func TestEntryFilterSkipsBodies(t *testing.T) {
+	events := append(
+		requestEvents("main", "1.1", "https://example.com/", 10),
+		requestEvents("popup", "2.1", "https://auth.example.com/", 11)...,
+	)
+
+	bodies := &recordingBodies{}
+	h, err := FromEventSource(NewSliceSource(events), WithBodyProvider(bodies), WithEntryFilter(func(entry har.Entry) bool {
+		return entry.Request.URL.Hostname() == "auth.example.com"
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"2.1"}, bodies.requestIDs)
+	require.Len(t, h.Log.Entries, 1)
+	require.Equal(t, "body", *h.Log.Entries[0].Response.Content.Text)
+	require.Len(t, h.Log.Pages, 1)
+	require.Equal(t, "page_2", h.Log.Pages[0].ID)
+}
+
 func TestNew(t *testing.T) {
 	har, err := New(logEntries)
 	require.NoError(t, err)
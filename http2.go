@@ -0,0 +1,40 @@
+package chromedriver2har
+
+import (
+	"strings"
+)
+
+const (
+	pseudoHeaderPrefix = ":"
+	initiatorTypePush  = "push"
+)
+
+func isPseudoHeader(name string) bool {
+	return strings.HasPrefix(name, pseudoHeaderPrefix)
+}
+
+func harHTTPVersion(protocol string) string {
+	protocol = strings.ToLower(protocol)
+
+	switch {
+	case protocol == "":
+		return ""
+	case protocol == "h2" || protocol == "h2c" || strings.HasPrefix(protocol, "http/2"):
+		return "HTTP/2"
+	case protocol == "h3" || strings.HasPrefix(protocol, "h3-") || strings.HasPrefix(protocol, "http/3") || protocol == "quic" || strings.HasPrefix(protocol, "http/2+quic"):
+		return "HTTP/3"
+	default:
+		return strings.ToUpper(protocol)
+	}
+}
+
+// compressedHeaders reports whether httpVersion sends headers through
+// HPACK or QPACK, whose wire size DevTools doesn't report separately.
+func compressedHeaders(httpVersion string) bool {
+	return httpVersion == "HTTP/2" || httpVersion == "HTTP/3"
+}
+
+func (rp *requestParams) pushed() bool {
+	timing := rp.networkResponseReceived.Response.Timing
+	return rp.networkRequestWillBeSent.Initiator.Type == initiatorTypePush || (timing != nil && timing.PushStart > 0)
+}
@@ -0,0 +1,74 @@
+package chromedriver2har
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHARHTTPVersion(t *testing.T) {
+	for protocol, expected := range map[string]string{
+		"h2":       "HTTP/2",
+		"h3-29":    "HTTP/3",
+		"h3":       "HTTP/3",
+		"http/1.1": "HTTP/1.1",
+		"":         "",
+	} {
+		require.Equal(t, expected, harHTTPVersion(protocol), protocol)
+	}
+}
+
+func TestPseudoHeaders(t *testing.T) {
+	headers := map[string]string{
+		":authority": "example.com",
+		":method":    "GET",
+		":path":      "/",
+		":scheme":    "https",
+		"cookie":     "a=1; b=2",
+		"accept":     "*/*",
+	}
+
+	harHeaders := harHeaders(headers)
+	require.Len(t, harHeaders, 6)
+	require.Equal(t, ":authority", harHeaders[0].Name)
+	require.Equal(t, "accept", harHeaders[4].Name)
+
+	require.Len(t, harCookies(headers), 2)
+
+	u, err := url.Parse("https://example.com/")
+	require.NoError(t, err)
+	require.Equal(t, len("GET / HTTP/1.1\r\ncookie: a=1; b=2\r\naccept: */*\r\n\r\n"), harRequestHeadersSize("GET", "HTTP/1.1", *u, headers))
+
+	protocol := "h2"
+	request, err := harRequest(&requestParams{
+		networkRequestWillBeSent: NetworkRequestWillBeSent{Request: Request{URL: u.String(), Method: "GET", Headers: headers}},
+		networkResponseReceived:  NetworkResponseReceived{Response: Response{Protocol: &protocol}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "HTTP/2", request.HTTPVersion)
+	require.Equal(t, -1, request.HeadersSize)
+}
+
+func TestHTTP2ResponseSizes(t *testing.T) {
+	protocol := "h2"
+	params := &requestParams{
+		networkResponseReceived: NetworkResponseReceived{
+			Response: Response{Status: 200, Protocol: &protocol, Headers: map[string]string{":status": "200", "content-type": "text/html"}},
+		},
+		networkDatasReceived:   []NetworkDataReceived{{DataLength: 2048}},
+		networkLoadingFinished: NetworkLoadingFinished{RequestID: "1", EncodedDataLength: 1024},
+	}
+
+	response, err := harResponse(params)
+	require.NoError(t, err)
+	require.Equal(t, -1, response.HeadersSize)
+	require.Equal(t, 1024, response.BodySize)
+	require.Equal(t, 1024, *response.Content.Compression)
+
+	custom := harEntryCustom(params)
+	require.NotContains(t, custom, CustomPushed)
+
+	params.networkRequestWillBeSent.Initiator.Type = initiatorTypePush
+	require.Equal(t, true, harEntryCustom(params)[CustomPushed])
+}
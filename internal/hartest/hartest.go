@@ -0,0 +1,134 @@
+// Package hartest builds HAR entries for tests.
+package hartest
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+)
+
+// Entry builds a har.Entry one field at a time, so fixtures only spell out
+// what the test depends on.
+type Entry struct {
+	entry har.Entry
+}
+
+// NewEntry starts an entry for a request. It panics if rawURL doesn't parse.
+func NewEntry(method, rawURL string) *Entry {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		panic(err)
+	}
+	return &Entry{entry: har.Entry{
+		Request: har.Request{Method: method, URL: har.URL{URL: *u}},
+	}}
+}
+
+func (e *Entry) Page(pageRef string) *Entry {
+	e.entry.PageRef = &pageRef
+	return e
+}
+
+func (e *Entry) Started(started time.Time) *Entry {
+	e.entry.StartedDateTime = har.Time{Time: started}
+	return e
+}
+
+func (e *Entry) Time(time float64) *Entry {
+	e.entry.Time = time
+	return e
+}
+
+func (e *Entry) Timings(timings har.Timings) *Entry {
+	e.entry.Timings = timings
+	return e
+}
+
+func (e *Entry) Connection(connection string) *Entry {
+	e.entry.Connection = &connection
+	return e
+}
+
+func (e *Entry) Custom(key string, value interface{}) *Entry {
+	if e.entry.Custom == nil {
+		e.entry.Custom = make(har.Custom)
+	}
+	e.entry.Custom[key] = value
+	return e
+}
+
+func (e *Entry) RequestHeader(name, value string) *Entry {
+	e.entry.Request.Headers = append(e.entry.Request.Headers, har.Header{Name: name, Value: value})
+	return e
+}
+
+func (e *Entry) RequestCookie(name, value string) *Entry {
+	e.entry.Request.Cookies = append(e.entry.Request.Cookies, har.Cookie{Name: name, Value: value})
+	return e
+}
+
+func (e *Entry) PostData(mimeType, text string) *Entry {
+	e.entry.Request.PostData = &har.PostData{MIMEType: mimeType, Text: text}
+	return e
+}
+
+func (e *Entry) Status(status int) *Entry {
+	e.entry.Response.Status = status
+	return e
+}
+
+func (e *Entry) StatusText(statusText string) *Entry {
+	e.entry.Response.StatusText = statusText
+	return e
+}
+
+func (e *Entry) ResponseHeader(name, value string) *Entry {
+	e.entry.Response.Headers = append(e.entry.Response.Headers, har.Header{Name: name, Value: value})
+	return e
+}
+
+func (e *Entry) BodySize(bodySize int) *Entry {
+	e.entry.Response.BodySize = bodySize
+	return e
+}
+
+func (e *Entry) Body(text string) *Entry {
+	e.entry.Response.Content.Text = &text
+	return e
+}
+
+func (e *Entry) Encoding(encoding string) *Entry {
+	e.entry.Response.Content.Encoding = &encoding
+	return e
+}
+
+func (e *Entry) ContentSize(size int) *Entry {
+	e.entry.Response.Content.Size = size
+	return e
+}
+
+func (e *Entry) Compression(compression int) *Entry {
+	e.entry.Response.Content.Compression = &compression
+	return e
+}
+
+func (e *Entry) MIMEType(mimeType string) *Entry {
+	e.entry.Response.Content.MIMEType = mimeType
+	return e
+}
+
+// Build returns the entry. Later calls on the builder don't affect it.
+func (e *Entry) Build() har.Entry {
+	entry := e.entry
+	entry.Request.Headers = append([]har.Header(nil), entry.Request.Headers...)
+	entry.Request.Cookies = append([]har.Cookie(nil), entry.Request.Cookies...)
+	entry.Response.Headers = append([]har.Header(nil), entry.Response.Headers...)
+	if entry.Custom != nil {
+		entry.Custom = make(har.Custom, len(e.entry.Custom))
+		for key, value := range e.entry.Custom {
+			entry.Custom[key] = value
+		}
+	}
+	return entry
+}
@@ -0,0 +1,180 @@
+package loadtest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+)
+
+// Headers k6 sets itself.
+var k6SkippedHeaders = map[string]bool{
+	"connection":     true,
+	"content-length": true,
+	"host":           true,
+}
+
+func K6(w io.Writer, h *har.HAR, opts Options) error {
+	steps := steps(h, opts)
+	tokens := tokens(steps)
+
+	var buf bytes.Buffer
+	buf.WriteString(`import http from "k6/http";
+import { sleep } from "k6";
+
+export const options = {
+  vus: 1,
+  iterations: 1,
+};
+
+function extract(body, pattern) {
+  const match = pattern.exec(body || "");
+  return match ? match[1] : undefined;
+}
+
+export default function () {
+  const vars = {
+`)
+	for _, t := range tokens {
+		fmt.Fprintf(&buf, "    %s: __ENV.%s || %s,\n", t.name, t.name, strconv.Quote(t.value))
+	}
+	buf.WriteString("  };\n  let responses;\n")
+
+	// Names of the cookies set by the responses written so far, which k6's
+	// cookie jar sends by itself.
+	jar := make(map[string]bool)
+
+	for _, s := range steps {
+		buf.WriteString("\n")
+		if s.thinkTime > 0 {
+			fmt.Fprintf(&buf, "  sleep(%s);\n\n", strconv.FormatFloat(s.thinkTime, 'f', -1, 64))
+		}
+		if s.pageRef != "" {
+			fmt.Fprintf(&buf, "  // %s\n", strings.TrimSpace(strings.Join([]string{s.pageRef, s.title}, " ")))
+		}
+
+		for i := range s.batches {
+			writeK6Batch(&buf, s.batches[i], tokens, jar)
+		}
+	}
+
+	buf.WriteString("}\n")
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func writeK6Batch(buf *bytes.Buffer, batch []har.Entry, tokens []*token, jar map[string]bool) {
+	if len(batch) == 1 {
+		fmt.Fprintf(buf, "  responses = [http.request(%s)];\n", k6Request(batch[0], tokens, jar))
+	} else {
+		buf.WriteString("  responses = http.batch([\n")
+		for _, entry := range batch {
+			fmt.Fprintf(buf, "    [%s],\n", k6Request(entry, tokens, jar))
+		}
+		buf.WriteString("  ]);\n")
+	}
+
+	for _, entry := range batch {
+		for _, name := range setCookieNames(entry) {
+			jar[name] = true
+		}
+	}
+
+	for i := range batch {
+		for _, t := range tokens {
+			if t.from != &batch[i] {
+				continue
+			}
+			switch t.source {
+			case sourceHeader:
+				fmt.Fprintf(buf, "  vars.%s = responses[%d].headers[%s] || vars.%s;\n", t.name, i, strconv.Quote(t.key), t.name)
+			case sourceCookie:
+				fmt.Fprintf(buf, "  vars.%s = ((responses[%d].cookies[%s] || [])[0] || {}).value || vars.%s;\n", t.name, i, strconv.Quote(t.key), t.name)
+			case sourceBody:
+				fmt.Fprintf(buf, "  vars.%s = extract(responses[%d].body, /%s/) || vars.%s;\n", t.name, i, strings.Replace(t.pattern, "/", `\/`, -1), t.name)
+			}
+		}
+	}
+}
+
+func k6Request(entry har.Entry, tokens []*token, jar map[string]bool) string {
+	var params []string
+	for _, header := range entry.Request.Headers {
+		name := strings.ToLower(header.Name)
+		if strings.HasPrefix(name, ":") || k6SkippedHeaders[name] {
+			continue
+		}
+		value := header.Value
+		if name == "cookie" {
+			if value = unjarredCookies(value, jar); value == "" {
+				continue
+			}
+		}
+		params = append(params, fmt.Sprintf("%s: %s", strconv.Quote(header.Name), templateLiteral(value, tokens)))
+	}
+
+	args := []string{strconv.Quote(entry.Request.Method), templateLiteral(entry.Request.URL.String(), tokens)}
+
+	body := "null"
+	if entry.Request.PostData != nil && entry.Request.PostData.Text != "" {
+		body = templateLiteral(entry.Request.PostData.Text, tokens)
+	}
+	if len(params) > 0 {
+		args = append(args, body, fmt.Sprintf("{ headers: { %s } }", strings.Join(params, ", ")))
+	} else if body != "null" {
+		args = append(args, body)
+	}
+
+	return strings.Join(args, ", ")
+}
+
+// unjarredCookies returns the cookies in a Cookie header that weren't set by
+// an earlier response, such as ones set by scripts, which k6 would not send.
+func unjarredCookies(header string, jar map[string]bool) string {
+	var kept []string
+	for _, cookie := range strings.Split(header, ";") {
+		cookie = strings.TrimSpace(cookie)
+		name := strings.TrimSpace(strings.SplitN(cookie, "=", 2)[0])
+		if cookie != "" && !jar[name] {
+			kept = append(kept, cookie)
+		}
+	}
+	return strings.Join(kept, "; ")
+}
+
+func setCookieNames(entry har.Entry) []string {
+	var names []string
+	for _, header := range entry.Response.Headers {
+		if !strings.EqualFold(header.Name, "Set-Cookie") {
+			continue
+		}
+		// Chrome joins repeated headers with newlines.
+		for _, line := range strings.Split(header.Value, "\n") {
+			name := strings.TrimSpace(strings.SplitN(strings.SplitN(line, ";", 2)[0], "=", 2)[0])
+			if name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	for _, cookie := range entry.Response.Cookies {
+		names = append(names, cookie.Name)
+	}
+	return names
+}
+
+// templateLiteral quotes s as a JavaScript template literal with token values
+// replaced by references to their variables.
+func templateLiteral(s string, tokens []*token) string {
+	literal := escapeTemplate(s)
+	for _, t := range tokens {
+		literal = strings.Replace(literal, escapeTemplate(t.value), "${vars."+t.name+"}", -1)
+	}
+	return "`" + literal + "`"
+}
+
+func escapeTemplate(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "`", "\\`", "${", `\${`).Replace(s)
+}
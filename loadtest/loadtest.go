@@ -0,0 +1,273 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+)
+
+const (
+	minTokenLength = 8
+	contextLength  = 24
+)
+
+var tokenNamePattern = regexp.MustCompile(`(?i)csrf|xsrf|authenticity_token|^_?token$`)
+
+type Options struct {
+	Pages []string
+}
+
+type step struct {
+	pageRef   string
+	title     string
+	thinkTime float64
+	batches   [][]har.Entry
+}
+
+type tokenSource int
+
+const (
+	sourceEnvironment tokenSource = iota
+	sourceHeader
+	sourceCookie
+	sourceBody
+)
+
+// token is a dynamic value sent in requests that can be captured from an
+// earlier response, or failing that supplied through the environment.
+type token struct {
+	name    string
+	value   string
+	source  tokenSource
+	from    *har.Entry
+	key     string
+	pattern string
+}
+
+func steps(h *har.HAR, opts Options) []step {
+	selected := make(map[string]bool, len(opts.Pages))
+	for _, page := range opts.Pages {
+		selected[page] = true
+	}
+
+	titles := make(map[string]string, len(h.Log.Pages))
+	for _, page := range h.Log.Pages {
+		titles[page.ID] = page.Title
+	}
+
+	var steps []step
+	var stepEnd, batchEnd time.Time
+	for _, entry := range har.SortedByStart(h.Log.Entries) {
+		scheme := entry.Request.URL.Scheme
+		if scheme != "http" && scheme != "https" {
+			continue
+		}
+
+		pageRef := ""
+		if entry.PageRef != nil {
+			pageRef = *entry.PageRef
+		}
+		if len(selected) > 0 && !selected[pageRef] {
+			continue
+		}
+
+		start := entry.StartedDateTime.Time
+		end := start.Add(time.Duration(entry.Time * float64(time.Millisecond)))
+
+		if len(steps) == 0 || steps[len(steps)-1].pageRef != pageRef {
+			s := step{pageRef: pageRef, title: titles[pageRef]}
+			if len(steps) > 0 {
+				s.thinkTime = math.Max(0, math.Round(start.Sub(stepEnd).Seconds()*10)/10)
+			}
+			steps = append(steps, s)
+		}
+
+		// The first request of a step is the navigation its other requests
+		// were discovered from, so it always gets a batch of its own. After
+		// that, requests that start before the current batch has finished were
+		// in flight together and are sent as one batch.
+		s := &steps[len(steps)-1]
+		if len(s.batches) <= 1 || !start.Before(batchEnd) {
+			s.batches = append(s.batches, nil)
+			batchEnd = end
+		}
+		s.batches[len(s.batches)-1] = append(s.batches[len(s.batches)-1], entry)
+		if end.After(batchEnd) {
+			batchEnd = end
+		}
+		if len(s.batches) == 1 || end.After(stepEnd) {
+			stepEnd = end
+		}
+	}
+	return steps
+}
+
+// tokens detects CSRF style values sent in request headers or bodies and finds
+// the earliest response they could have been read from.
+func tokens(steps []step) []*token {
+	var batches [][]har.Entry
+	for _, s := range steps {
+		batches = append(batches, s.batches...)
+	}
+
+	var found []*token
+	seen := make(map[string]bool)
+	names := make(map[string]bool)
+	for i, batch := range batches {
+		for _, entry := range batch {
+			for _, candidate := range tokenCandidates(entry.Request) {
+				if seen[candidate.value] {
+					continue
+				}
+				seen[candidate.value] = true
+
+				t := &token{name: variableName(candidate.name, names), value: candidate.value}
+				findEarlierSource(t, batches[:i])
+				found = append(found, t)
+			}
+		}
+	}
+	return found
+}
+
+// Only responses from earlier batches can have supplied a value, since
+// requests within a batch were in flight at the same time.
+func findEarlierSource(t *token, batches [][]har.Entry) {
+	for i := range batches {
+		for j := range batches[i] {
+			if findSource(t, &batches[i][j]) {
+				return
+			}
+		}
+	}
+}
+
+type candidate struct {
+	name  string
+	value string
+}
+
+func tokenCandidates(request har.Request) []candidate {
+	var candidates []candidate
+	add := func(name, value string) {
+		if tokenNamePattern.MatchString(name) && len(value) >= minTokenLength {
+			candidates = append(candidates, candidate{name, value})
+		}
+	}
+
+	for _, header := range request.Headers {
+		add(header.Name, header.Value)
+	}
+
+	if request.PostData == nil {
+		return candidates
+	}
+
+	for _, param := range request.PostData.Params {
+		if param.Value != nil {
+			add(param.Name, *param.Value)
+		}
+	}
+
+	text := request.PostData.Text
+	if values, err := url.ParseQuery(text); err == nil && strings.Contains(request.PostData.MIMEType, "x-www-form-urlencoded") {
+		for name := range values {
+			add(name, values.Get(name))
+		}
+	}
+
+	var fields map[string]interface{}
+	if json.Unmarshal([]byte(text), &fields) == nil {
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if value, ok := fields[name].(string); ok {
+				add(name, value)
+			}
+		}
+	}
+
+	return candidates
+}
+
+func findSource(t *token, entry *har.Entry) bool {
+	for _, header := range entry.Response.Headers {
+		name := strings.ToLower(header.Name)
+		if name == "set-cookie" {
+			for _, line := range strings.Split(header.Value, "\n") {
+				nameValue := strings.SplitN(strings.SplitN(line, ";", 2)[0], "=", 2)
+				if len(nameValue) == 2 && strings.TrimSpace(nameValue[1]) == t.value {
+					t.source, t.from, t.key = sourceCookie, entry, strings.TrimSpace(nameValue[0])
+					return true
+				}
+			}
+			continue
+		}
+		if header.Value == t.value && !strings.HasPrefix(name, ":") {
+			t.source, t.from, t.key = sourceHeader, entry, http.CanonicalHeaderKey(header.Name)
+			return true
+		}
+	}
+
+	text := entry.Response.Content.Text
+	if text == nil || (entry.Response.Content.Encoding != nil && *entry.Response.Content.Encoding != "") {
+		return false
+	}
+
+	i := strings.Index(*text, t.value)
+	if i < 0 {
+		return false
+	}
+
+	start := i - contextLength
+	if start < 0 {
+		start = 0
+	}
+	context := (*text)[start:i]
+	if nl := strings.LastIndexAny(context, "\r\n"); nl >= 0 {
+		context = context[nl+1:]
+	}
+	if context == "" {
+		return false
+	}
+
+	t.source, t.from = sourceBody, entry
+	t.pattern = regexp.QuoteMeta(context) + `([^"'&<>\s]+)`
+	return true
+}
+
+// variableName turns a parameter or header name like X-CSRF-Token into
+// X_CSRF_TOKEN, unique among names.
+func variableName(name string, names map[string]bool) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+			b.WriteByte('_')
+		}
+	}
+
+	base := strings.Trim(b.String(), "_")
+	if base == "" || (base[0] >= '0' && base[0] <= '9') {
+		base = "TOKEN_" + base
+	}
+
+	unique := base
+	for i := 2; names[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", base, i)
+	}
+	names[unique] = true
+	return unique
+}
@@ -0,0 +1,92 @@
+package loadtest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/internal/hartest"
+	"github.com/stretchr/testify/require"
+)
+
+func capture() *har.HAR {
+	start := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	ms := func(n int) time.Time { return start.Add(time.Duration(n) * time.Millisecond) }
+
+	form := `<form><input type="hidden" name="authenticity_token" value="f0rmT0ken123"></form>`
+	document := hartest.NewEntry("GET", "https://example.com/login").Page("page_1").Started(ms(0)).Time(100).
+		RequestHeader(":authority", "example.com").
+		RequestHeader("Accept", "text/html").
+		Status(200).
+		ResponseHeader("Set-Cookie", "XSRF-TOKEN=c00kieT0ken456; Path=/").
+		Body(form).
+		Build()
+
+	script := hartest.NewEntry("GET", "https://example.com/app.js").Page("page_1").Started(ms(120)).Time(50).Status(200).Build()
+	style := hartest.NewEntry("GET", "https://example.com/app.css").Page("page_1").Started(ms(130)).Time(30).Status(200).Build()
+	inline := hartest.NewEntry("GET", "data:image/png;base64,AAAA").Page("page_1").Started(ms(140)).Status(200).Build()
+
+	login := hartest.NewEntry("POST", "https://example.com/session").Page("page_2").Started(ms(2300)).Time(80).
+		RequestHeader("X-XSRF-Token", "c00kieT0ken456").
+		RequestHeader("Cookie", "XSRF-TOKEN=c00kieT0ken456").
+		RequestHeader("X-Csrf-Token", "unkn0wnT0ken789").
+		PostData("application/x-www-form-urlencoded", "user=ada&authenticity_token=f0rmT0ken123").
+		Status(200).
+		Build()
+
+	return &har.HAR{Log: har.Log{
+		Pages:   []har.Page{{ID: "page_1", Title: "https://example.com/login"}, {ID: "page_2", Title: "https://example.com/session"}},
+		Entries: []har.Entry{login, style, document, inline, script},
+	}}
+}
+
+func TestK6(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, K6(&buf, capture(), Options{}))
+	script := buf.String()
+
+	require.Contains(t, script, `    X_XSRF_TOKEN: __ENV.X_XSRF_TOKEN || "c00kieT0ken456",
+    X_CSRF_TOKEN: __ENV.X_CSRF_TOKEN || "unkn0wnT0ken789",
+    AUTHENTICITY_TOKEN: __ENV.AUTHENTICITY_TOKEN || "f0rmT0ken123",`)
+	require.Contains(t, script, "  // page_1 https://example.com/login\n"+
+		"  responses = [http.request(\"GET\", `https://example.com/login`, null, { headers: { \"Accept\": `text/html` } })];\n"+
+		"  vars.X_XSRF_TOKEN = ((responses[0].cookies[\"XSRF-TOKEN\"] || [])[0] || {}).value || vars.X_XSRF_TOKEN;\n"+
+		"  vars.AUTHENTICITY_TOKEN = extract(responses[0].body, /henticity_token\" value=\"([^\"'&<>\\s]+)/) || vars.AUTHENTICITY_TOKEN;\n"+
+		"  responses = http.batch([\n"+
+		"    [\"GET\", `https://example.com/app.js`],\n"+
+		"    [\"GET\", `https://example.com/app.css`],\n"+
+		"  ]);\n"+
+		"\n"+
+		"  sleep(2.1);\n")
+	require.Contains(t, script, "`user=ada&authenticity_token=${vars.AUTHENTICITY_TOKEN}`")
+	require.Contains(t, script, "\"X-XSRF-Token\": `${vars.X_XSRF_TOKEN}`, \"X-Csrf-Token\": `${vars.X_CSRF_TOKEN}`")
+	require.NotContains(t, script, "data:image")
+	require.NotContains(t, script, `"Cookie"`)
+
+	h := capture()
+	for i, header := range h.Log.Entries[0].Request.Headers {
+		if header.Name == "Cookie" {
+			h.Log.Entries[0].Request.Headers[i].Value = "XSRF-TOKEN=c00kieT0ken456; consent=yes"
+		}
+	}
+	buf.Reset()
+	require.NoError(t, K6(&buf, h, Options{}))
+	require.Contains(t, buf.String(), "\"Cookie\": `consent=yes`")
+
+	buf.Reset()
+	require.NoError(t, K6(&buf, capture(), Options{Pages: []string{"page_2"}}))
+	require.NotContains(t, buf.String(), "app.js")
+	require.NotContains(t, buf.String(), "sleep(")
+}
+
+func TestVegeta(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Vegeta(&buf, capture(), Options{}))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, `{"method":"GET","url":"https://example.com/login","header":{"Accept":["text/html"]}}`, lines[0])
+	require.Equal(t, `{"method":"POST","url":"https://example.com/session","body":"dXNlcj1hZGEmYXV0aGVudGljaXR5X3Rva2VuPWYwcm1UMGtlbjEyMw==","header":{"Cookie":["XSRF-TOKEN=c00kieT0ken456"],"X-Csrf-Token":["unkn0wnT0ken789"],"X-Xsrf-Token":["c00kieT0ken456"]}}`, lines[3])
+}
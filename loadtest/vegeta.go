@@ -0,0 +1,65 @@
+package loadtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/pkg/errors"
+)
+
+var vegetaSkippedHeaders = map[string]bool{
+	"connection":     true,
+	"content-length": true,
+}
+
+type vegetaTarget struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Body   string      `json:"body,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+}
+
+// Vegeta writes targets in Vegeta's JSON format, one per line, for use with
+// `vegeta attack -format=json`. Vegeta replays targets at a fixed rate, so
+// think time and batches don't apply and dynamic values keep their recorded
+// values.
+func Vegeta(w io.Writer, h *har.HAR, opts Options) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+
+	for _, s := range steps(h, opts) {
+		for _, batch := range s.batches {
+			for _, entry := range batch {
+				if err := encoder.Encode(newVegetaTarget(entry)); err != nil {
+					return errors.Wrap(err, "failed to encode target")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func newVegetaTarget(entry har.Entry) vegetaTarget {
+	target := vegetaTarget{
+		Method: entry.Request.Method,
+		URL:    entry.Request.URL.String(),
+		Header: make(http.Header),
+	}
+
+	for _, header := range entry.Request.Headers {
+		name := strings.ToLower(header.Name)
+		if strings.HasPrefix(name, ":") || vegetaSkippedHeaders[name] {
+			continue
+		}
+		target.Header.Add(header.Name, header.Value)
+	}
+
+	if entry.Request.PostData != nil && entry.Request.PostData.Text != "" {
+		target.Body = base64.StdEncoding.EncodeToString([]byte(entry.Request.PostData.Text))
+	}
+	return target
+}
@@ -0,0 +1,191 @@
+package merge
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/pkg/errors"
+)
+
+const pageIDFormat = "page_%d"
+
+var requestIDKeys = []string{
+	chromedriver2har.CustomRequestID,
+	chromedriver2har.CustomServiceWorkerRequestID,
+	chromedriver2har.CustomSatisfiedRequestID,
+}
+
+type Source struct {
+	Name string
+	HAR  *har.HAR
+}
+
+type Options struct {
+	GroupBySource bool
+}
+
+func FromEventSource(name string, source chromedriver2har.EventSource, opts ...chromedriver2har.Option) (Source, error) {
+	h, err := chromedriver2har.FromEventSource(source, opts...)
+	if err != nil {
+		return Source{}, errors.Wrapf(err, "failed to convert %q", name)
+	}
+	return Source{Name: name, HAR: h}, nil
+}
+
+func Merge(sources []Source, opts Options) (*har.HAR, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("no sources to merge")
+	}
+
+	merged := &har.HAR{Log: har.Log{
+		Version: sources[0].HAR.Log.Version,
+		Creator: sources[0].HAR.Log.Creator,
+		Browser: sources[0].HAR.Log.Browser,
+		Pages:   make([]har.Page, 0),
+		Entries: make([]har.Entry, 0),
+	}}
+
+	usedRequestIDs := make(map[string]bool)
+	for i, source := range sources {
+		if source.HAR == nil {
+			return nil, errors.Errorf("source %q has no HAR", source.Name)
+		}
+
+		requestIDs := renameRequestIDs(source.HAR.Log.Entries, usedRequestIDs, i+1)
+
+		if opts.GroupBySource {
+			merged.Log.Pages = append(merged.Log.Pages, sourcePage(source, sourcePageID(i, "")))
+		} else {
+			for _, page := range source.HAR.Log.Pages {
+				page.ID = sourcePageID(i, page.ID)
+				merged.Log.Pages = append(merged.Log.Pages, page)
+			}
+		}
+
+		for _, entry := range source.HAR.Log.Entries {
+			entry.Custom = renameCustom(entry.Custom, requestIDs)
+			switch {
+			case opts.GroupBySource:
+				pageRef := sourcePageID(i, "")
+				entry.PageRef = &pageRef
+			case entry.PageRef != nil:
+				pageRef := sourcePageID(i, *entry.PageRef)
+				entry.PageRef = &pageRef
+			}
+			merged.Log.Entries = append(merged.Log.Entries, entry)
+		}
+	}
+
+	renumberPages(merged.Log.Pages, merged.Log.Entries)
+	merged.Log.Entries = har.SortedByStart(merged.Log.Entries)
+
+	return merged, nil
+}
+
+func sourcePageID(sourceIndex int, pageID string) string {
+	return fmt.Sprintf("%d/%s", sourceIndex, pageID)
+}
+
+func sourcePage(source Source, id string) har.Page {
+	page := har.Page{ID: id, Title: source.Name}
+
+	var started []har.Time
+	for _, p := range source.HAR.Log.Pages {
+		started = append(started, p.StartedDateTime)
+	}
+	for _, entry := range source.HAR.Log.Entries {
+		started = append(started, entry.StartedDateTime)
+	}
+	for i, t := range started {
+		if i == 0 || t.Before(page.StartedDateTime.Time) {
+			page.StartedDateTime = t
+		}
+	}
+	return page
+}
+
+// Pages are numbered after sorting so that page_1 is always the earliest page,
+// matching what a single conversion would produce. Entries referencing a page
+// that doesn't exist in their source lose their pageref.
+func renumberPages(pages []har.Page, entries []har.Entry) {
+	sort.SliceStable(pages, func(i, j int) bool {
+		return pages[i].StartedDateTime.Before(pages[j].StartedDateTime.Time)
+	})
+
+	pageIDs := make(map[string]string, len(pages))
+	for i := range pages {
+		id := fmt.Sprintf(pageIDFormat, i+1)
+		pageIDs[pages[i].ID] = id
+		pages[i].ID = id
+	}
+
+	for i := range entries {
+		if entries[i].PageRef == nil {
+			continue
+		}
+		if id, ok := pageIDs[*entries[i].PageRef]; ok {
+			entries[i].PageRef = &id
+		} else {
+			entries[i].PageRef = nil
+		}
+	}
+}
+
+// Request IDs are only unique within a browser session, so IDs already used by
+// an earlier source get a suffix naming the source they came from.
+func renameRequestIDs(entries []har.Entry, used map[string]bool, sourceIndex int) map[string]string {
+	renamed := make(map[string]string)
+	var ids []string
+	for _, entry := range entries {
+		for _, key := range requestIDKeys {
+			if id := entry.Custom.String(key); id != "" {
+				if _, ok := renamed[id]; !ok {
+					renamed[id] = id
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+
+	reserved := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		reserved[id] = true
+	}
+
+	for _, id := range ids {
+		if !used[id] {
+			continue
+		}
+
+		newID := fmt.Sprintf("%s-%d", id, sourceIndex)
+		for n := 2; used[newID] || reserved[newID]; n++ {
+			newID = fmt.Sprintf("%s-%d-%d", id, sourceIndex, n)
+		}
+		renamed[id] = newID
+		reserved[newID] = true
+	}
+
+	for id := range reserved {
+		used[id] = true
+	}
+	return renamed
+}
+
+func renameCustom(custom har.Custom, requestIDs map[string]string) har.Custom {
+	if custom == nil {
+		return nil
+	}
+
+	renamed := make(har.Custom, len(custom))
+	for key, value := range custom {
+		renamed[key] = value
+	}
+	for _, key := range requestIDKeys {
+		if id := custom.String(key); id != "" {
+			renamed[key] = requestIDs[id]
+		}
+	}
+	return renamed
+}
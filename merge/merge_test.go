@@ -0,0 +1,63 @@
+package merge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/stretchr/testify/require"
+)
+
+func session(start time.Time, requestIDs ...string) *har.HAR {
+	h := &har.HAR{Log: har.Log{
+		Version: "1.2",
+		Pages:   []har.Page{{ID: "page_1", StartedDateTime: har.Time{Time: start}}},
+	}}
+	for i, requestID := range requestIDs {
+		pageRef := "page_1"
+		h.Log.Entries = append(h.Log.Entries, har.Entry{
+			PageRef:         &pageRef,
+			StartedDateTime: har.Time{Time: start.Add(time.Duration(2*i) * time.Second)},
+			Custom:          har.Custom{chromedriver2har.CustomRequestID: requestID},
+		})
+	}
+	return h
+}
+
+func TestMerge(t *testing.T) {
+	start := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	first := session(start.Add(time.Second), "1000.1", "1000.2")
+	second := session(start, "1000.1", "1000.2-2", "1000.3")
+	second.Log.Entries[2].Custom[chromedriver2har.CustomSatisfiedRequestID] = "1000.1"
+
+	sources := []Source{{Name: "first", HAR: first}, {Name: "second", HAR: second}}
+
+	merged, err := Merge(sources, Options{})
+	require.NoError(t, err)
+	require.Len(t, merged.Log.Pages, 2)
+	require.Equal(t, "page_1", merged.Log.Pages[0].ID)
+	require.Equal(t, start, merged.Log.Pages[0].StartedDateTime.Time)
+
+	var requestIDs, pageRefs []string
+	for _, entry := range merged.Log.Entries {
+		requestIDs = append(requestIDs, entry.Custom.String(chromedriver2har.CustomRequestID))
+		pageRefs = append(pageRefs, *entry.PageRef)
+	}
+	require.Equal(t, []string{"1000.1-2", "1000.1", "1000.2-2", "1000.2", "1000.3"}, requestIDs)
+	require.Equal(t, []string{"page_1", "page_2", "page_1", "page_2", "page_1"}, pageRefs)
+	require.Equal(t, "1000.1-2", merged.Log.Entries[4].Custom.String(chromedriver2har.CustomSatisfiedRequestID))
+
+	require.Equal(t, "1000.1", second.Log.Entries[0].Custom.String(chromedriver2har.CustomRequestID))
+	require.Equal(t, "page_1", *second.Log.Entries[0].PageRef)
+
+	grouped, err := Merge(sources, Options{GroupBySource: true})
+	require.NoError(t, err)
+	require.Len(t, grouped.Log.Pages, 2)
+	require.Equal(t, "second", grouped.Log.Pages[0].Title)
+	require.Equal(t, "first", grouped.Log.Pages[1].Title)
+	require.Equal(t, "page_2", *grouped.Log.Entries[1].PageRef)
+
+	_, err = Merge(nil, Options{})
+	require.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package chromedriver2har
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+)
+
+type MixedContentKind string
+
+const (
+	MixedContentBlockable           MixedContentKind = "blockable"
+	MixedContentOptionallyBlockable MixedContentKind = "optionally-blockable"
+	InsecureFormPost                MixedContentKind = "insecure-form-post"
+	InsecureSubresource             MixedContentKind = "insecure-subresource"
+)
+
+const resourceTypeDocument = "Document"
+
+// ClassifyMixedContent reports how an entry loaded into documentURL is mixed
+// or insecure: Chrome's own mixed content classification when it made one,
+// otherwise a form posted from an HTTPS page over HTTP, or an HTTP
+// subresource of an HTTPS page.
+func ClassifyMixedContent(entry har.Entry, documentURL string) (MixedContentKind, bool) {
+	switch MixedContentKind(entry.Custom.String(CustomMixedContentType)) {
+	case MixedContentBlockable:
+		return MixedContentBlockable, true
+	case MixedContentOptionallyBlockable:
+		return MixedContentOptionallyBlockable, true
+	}
+
+	if entry.Request.URL.Scheme != "http" {
+		return "", false
+	}
+
+	if entry.Custom.String(CustomResourceType) == resourceTypeDocument {
+		if entry.Request.Method == "POST" && secureReferrer(entry.Request.Headers) {
+			return InsecureFormPost, true
+		}
+		return "", false
+	}
+
+	if isSecureURL(documentURL) {
+		return InsecureSubresource, true
+	}
+	return "", false
+}
+
+func secureReferrer(headers []har.Header) bool {
+	for _, header := range headers {
+		if strings.EqualFold(header.Name, "Origin") || strings.EqualFold(header.Name, "Referer") {
+			if isSecureURL(header.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isSecureURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.Scheme == "https"
+}
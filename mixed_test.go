@@ -0,0 +1,26 @@
+package chromedriver2har
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMixedContentAnnotation(t *testing.T) {
+	document := requestEvents("main", "1.1", "https://example.com/", 10)
+	script := requestEvents("main", "1.2", "http://cdn.example.com/app.js", 10.1)
+	script[0].Params = json.RawMessage(strings.Replace(string(script[0].Params), `"documentURL":"http://cdn.example.com/app.js"`, `"documentURL":"https://example.com/"`, 1))
+	image := requestEvents("main", "1.3", "http://cdn.example.com/logo.png", 10.2)
+	image[0].Params = json.RawMessage(strings.Replace(string(image[0].Params), `"method":"GET"`, `"method":"GET","mixedContentType":"optionally-blockable"`, 1))
+
+	events := append(append(document, script...), image...)
+	h, err := FromEventSource(NewSliceSource(events))
+	require.NoError(t, err)
+	require.Len(t, h.Log.Entries, 3)
+
+	require.NotContains(t, h.Log.Entries[0].Custom, CustomMixedContent)
+	require.Equal(t, "insecure-subresource", h.Log.Entries[1].Custom.String(CustomMixedContent))
+	require.Equal(t, "optionally-blockable", h.Log.Entries[2].Custom.String(CustomMixedContent))
+}
@@ -0,0 +1,151 @@
+package mockgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/pkg/errors"
+)
+
+const defaultPackage = "mocks"
+
+type Options struct {
+	Package   string
+	MatchBody bool
+}
+
+type host struct {
+	Name      string
+	Host      string
+	Responses []response
+}
+
+type response struct {
+	Method      string
+	Target      string
+	RequestBody string
+	Status      int
+	Header      http.Header
+	HeaderNames []string
+	Body        string
+}
+
+func Generate(w io.Writer, h *har.HAR, opts Options) error {
+	if opts.Package == "" {
+		opts.Package = defaultPackage
+	}
+
+	hosts, err := groupByHost(h.Log.Entries)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	err = sourceTemplate.Execute(&buf, struct {
+		Package   string
+		MatchBody bool
+		Hosts     []*host
+	}{opts.Package, opts.MatchBody, hosts})
+	if err != nil {
+		return errors.Wrap(err, "failed to execute template")
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "failed to format generated source")
+	}
+
+	_, err = w.Write(source)
+	return err
+}
+
+func groupByHost(entries []har.Entry) ([]*host, error) {
+	byHost := make(map[string]*host)
+	names := make(map[string]bool)
+	var hosts []*host
+
+	for _, entry := range entries {
+		if entry.Response.Status == 0 || entry.Request.URL.Host == "" {
+			continue
+		}
+
+		body, err := chromedriver2har.ContentBody(entry.Response.Content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode body of %s", entry.Request.URL.String())
+		}
+
+		requestBody := ""
+		if entry.Request.PostData != nil {
+			requestBody = entry.Request.PostData.Text
+		}
+
+		header := chromedriver2har.ResponseHeader(entry.Response)
+		headerNames := make([]string, 0, len(header))
+		for name := range header {
+			headerNames = append(headerNames, name)
+		}
+		sort.Strings(headerNames)
+
+		u := entry.Request.URL
+		hostName := strings.ToLower(u.Host)
+		group, ok := byHost[hostName]
+		if !ok {
+			group = &host{Name: identifier(hostName, names), Host: hostName}
+			byHost[hostName] = group
+			hosts = append(hosts, group)
+		}
+
+		group.Responses = append(group.Responses, response{
+			Method:      entry.Request.Method,
+			Target:      u.EscapedPath() + "?" + u.Query().Encode(),
+			RequestBody: requestBody,
+			Status:      entry.Response.Status,
+			Header:      header,
+			HeaderNames: headerNames,
+			Body:        string(body),
+		})
+	}
+
+	if len(hosts) == 0 {
+		return nil, errors.New("no completed entries to generate mocks from")
+	}
+	return hosts, nil
+}
+
+// identifier turns a host such as api.example.com:8443 into an exported Go
+// identifier like ApiExampleCom8443, unique among names.
+func identifier(hostName string, names map[string]bool) string {
+	var name strings.Builder
+	upper := true
+	for _, r := range hostName {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		name.WriteRune(r)
+	}
+
+	base := name.String()
+	if base == "" || !unicode.IsLetter([]rune(base)[0]) {
+		base = "Host" + base
+	}
+
+	unique := base
+	for i := 2; names[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", base, i)
+	}
+	names[unique] = true
+	return unique
+}
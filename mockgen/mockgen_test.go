@@ -0,0 +1,140 @@
+package mockgen
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/internal/hartest"
+	"github.com/stretchr/testify/require"
+)
+
+// generatedTest exercises the generated package through its public API.
+const generatedTest = `package clientmocks
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func do(t *testing.T, client *http.Client, method, url, body string) (int, string, http.Header) {
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response.StatusCode, string(data), response.Header
+}
+
+func expect(t *testing.T, client *http.Client, method, url, body string, status int, responseBody string) {
+	t.Helper()
+	if gotStatus, gotBody, _ := do(t, client, method, url, body); gotStatus != status || gotBody != responseBody {
+		t.Errorf("%s %s: got %d %q, want %d %q", method, url, gotStatus, gotBody, status, responseBody)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	client := &http.Client{Transport: NewTransport()}
+
+	_, _, header := do(t, client, "GET", "https://api.example.com/v1/users?a=1&b=2", "")
+	if header.Get("Content-Type") != "application/json" {
+		t.Errorf("got Content-Type %q", header.Get("Content-Type"))
+	}
+	expect(t, client, "GET", "https://api.example.com/v1/users?b=2&a=1", "", 200, "{\"id\":1}")
+	expect(t, client, "POST", "https://api.example.com/v1/users", "{\"name\":\"ada\"}", 201, "{\"id\":2}")
+	expect(t, client, "POST", "https://api.example.com/v1/users", "{\"name\":\"bob\"}", 501, "no recorded response for POST https://api.example.com/v1/users\n")
+	expect(t, client, "GET", "https://cdn.example.com:8443/logo.png", "", 404, "missing")
+	expect(t, client, "GET", "https://unknown.example.com/", "", 404, "404 page not found\n")
+
+	for _, body := range []string{"first", "second", "second"} {
+		expect(t, client, "GET", "https://api.example.com/v1/poll", "", 200, body)
+	}
+}
+
+func TestServer(t *testing.T) {
+	server := NewApiExampleComServer()
+	defer server.Close()
+	expect(t, server.Client(), "GET", server.URL+"/v1/users?a=1&b=2", "", 200, "{\"id\":1}")
+}
+`
+
+func TestGenerate(t *testing.T) {
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{
+		hartest.NewEntry("GET", "https://api.example.com/v1/users?b=2&a=1").Status(200).ResponseHeader("content-type", "application/json").Body(`{"id":1}`).Build(),
+		hartest.NewEntry("GET", "https://cdn.example.com:8443/logo.png").Status(404).Body("missing").Build(),
+		hartest.NewEntry("POST", "https://api.example.com/v1/users").PostData("", `{"name":"ada"}`).Status(201).Body(`{"id":2}`).Build(),
+		hartest.NewEntry("GET", "https://api.example.com/v1/failed").Body("").Build(),
+		hartest.NewEntry("GET", "https://api.example.com/v1/poll").Status(200).Body("first").Build(),
+		hartest.NewEntry("GET", "https://api.example.com/v1/poll").Status(200).Body("second").Build(),
+	}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, Generate(&buf, h, Options{Package: "clientmocks", MatchBody: true}))
+
+	_, err := parser.ParseFile(token.NewFileSet(), "mocks.go", buf.Bytes(), 0)
+	require.NoError(t, err)
+
+	source := buf.String()
+	require.Contains(t, source, "package clientmocks")
+	require.Contains(t, source, `"api.example.com":      NewApiExampleComHandler(),`)
+	require.Contains(t, source, `"cdn.example.com:8443": NewCdnExampleCom8443Handler(),`)
+	require.Contains(t, source, `target:      "/v1/users?a=1&b=2",`)
+	require.Contains(t, source, `requestBody: "{\"name\":\"ada\"}",`)
+	require.Contains(t, source, `"Content-Type": []string{"application/json"},`)
+	require.Contains(t, source, "if response.requestBody != string(requestBody) {")
+	require.NotContains(t, source, "/v1/failed")
+
+	runGenerated(t, buf.Bytes())
+
+	require.Error(t, Generate(&buf, &har.HAR{}, Options{}))
+}
+
+// runGenerated compiles the generated source in a scratch module and runs
+// generatedTest against it.
+func runGenerated(t *testing.T, source []byte) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	dir, err := ioutil.TempDir("", "mockgen")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for name, data := range map[string][]byte{
+		"go.mod":        []byte("module clientmocks\n\ngo 1.16\n"),
+		"mocks.go":      source,
+		"mocks_test.go": []byte(generatedTest),
+	} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), data, 0644))
+	}
+
+	cmd := exec.Command(goTool, "test", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GO111MODULE=on", "GOFLAGS=-mod=mod", "GOWORK=off", "GOPROXY=off")
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestIdentifier(t *testing.T) {
+	names := make(map[string]bool)
+	require.Equal(t, "ApiExampleCom", identifier("api.example.com", names))
+	require.Equal(t, "ApiExampleCom2", identifier("api-example.com", names))
+	require.Equal(t, "Host127001", identifier("127.0.0.1", names))
+}
@@ -0,0 +1,136 @@
+package mockgen
+
+import (
+	"text/template"
+)
+
+var sourceTemplate = template.Must(template.New("source").Parse(`// Code generated by chromedriver2har mock; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"io"
+{{- if .MatchBody}}
+	"io/ioutil"
+{{- end}}
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+type recordedResponse struct {
+	method      string
+	target      string
+	requestBody string
+	status      int
+	header      http.Header
+	body        string
+}
+
+// recordedHandler answers repeated requests with their recorded responses in
+// order, repeating the last one once they run out.
+type recordedHandler struct {
+	mu        sync.Mutex
+	responses []recordedResponse
+	served    []bool
+}
+
+func newRecordedHandler(responses []recordedResponse) *recordedHandler {
+	return &recordedHandler{responses: responses, served: make([]bool, len(responses))}
+}
+
+func (h *recordedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+{{- if .MatchBody}}
+	requestBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+{{- end}}
+	target := r.URL.EscapedPath() + "?" + r.URL.Query().Encode()
+
+	h.mu.Lock()
+	match := -1
+	for i, response := range h.responses {
+		if response.method != r.Method || response.target != target {
+			continue
+		}
+{{- if .MatchBody}}
+		if response.requestBody != string(requestBody) {
+			continue
+		}
+{{- end}}
+		match = i
+		if !h.served[i] {
+			break
+		}
+	}
+	if match >= 0 {
+		h.served[match] = true
+	}
+	h.mu.Unlock()
+
+	if match < 0 {
+		http.Error(w, "no recorded response for "+r.Method+" "+r.URL.String(), http.StatusNotImplemented)
+		return
+	}
+
+	response := h.responses[match]
+	for name, values := range response.header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(response.status)
+	io.WriteString(w, response.body)
+}
+
+type recordedTransport map[string]http.Handler
+
+func (t recordedTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	handler, ok := t[r.URL.Host]
+	if !ok {
+		handler = http.NotFoundHandler()
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, r)
+
+	response := recorder.Result()
+	response.Request = r
+	return response, nil
+}
+
+// NewTransport returns a RoundTripper answering requests to every recorded host.
+func NewTransport() http.RoundTripper {
+	return recordedTransport{
+{{- range .Hosts}}
+		{{printf "%q" .Host}}: New{{.Name}}Handler(),
+{{- end}}
+	}
+}
+{{range .Hosts}}
+// New{{.Name}}Handler returns a handler replaying requests recorded for {{.Host}}.
+func New{{.Name}}Handler() http.Handler {
+	return newRecordedHandler([]recordedResponse{
+{{- range .Responses}}
+		{
+			method:      {{printf "%q" .Method}},
+			target:      {{printf "%q" .Target}},
+			requestBody: {{printf "%q" .RequestBody}},
+			status:      {{.Status}},
+			header: http.Header{
+{{- $header := .Header}}
+{{- range .HeaderNames}}
+				{{printf "%q" .}}: {{printf "%#v" (index $header .)}},
+{{- end}}
+			},
+			body: {{printf "%q" .Body}},
+		},
+{{- end}}
+	})
+}
+
+// New{{.Name}}Server starts a test server replaying requests recorded for {{.Host}}.
+func New{{.Name}}Server() *httptest.Server {
+	return httptest.NewServer(New{{.Name}}Handler())
+}
+{{end}}`))
@@ -0,0 +1,328 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+)
+
+const (
+	defaultTitle   = "Inferred API"
+	defaultVersion = "0.0.0"
+
+	mediaTypeJSON = "application/json"
+)
+
+var (
+	numericSegment     = regexp.MustCompile(`^[0-9]+$`)
+	identifierNonWords = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+type Options struct {
+	Title   string
+	Version string
+}
+
+type operation struct {
+	method   string
+	path     string
+	servers  map[string]bool
+	params   []Parameter
+	samples  int
+	query    map[string]*queryParam
+	request  *Schema
+	requests int
+	bodies   int
+	statuses map[int]*Schema
+}
+
+type queryParam struct {
+	count  int
+	schema *Schema
+}
+
+// Infer builds an OpenAPI document from the JSON API calls in a HAR. Numeric
+// and UUID path segments become path parameters, so requests for different
+// resources of the same kind share an operation. When calls went to more than
+// one server, each operation lists the servers it was seen on.
+func Infer(h *har.HAR, opts Options) *Document {
+	if opts.Title == "" {
+		opts.Title = defaultTitle
+	}
+	if opts.Version == "" {
+		opts.Version = defaultVersion
+	}
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: opts.Title, Version: opts.Version},
+		Paths:   make(map[string]PathItem),
+	}
+
+	operations := make(map[string]*operation)
+	var order []string
+	servers := make(map[string]bool)
+
+	for _, entry := range h.Log.Entries {
+		if !isJSONAPICall(entry) {
+			continue
+		}
+
+		u := entry.Request.URL
+		server := u.Scheme + "://" + u.Host
+		if !servers[server] {
+			servers[server] = true
+			doc.Servers = append(doc.Servers, Server{URL: server})
+		}
+
+		path, params := pathTemplate(u.Path)
+		method := strings.ToLower(entry.Request.Method)
+		key := method + " " + path
+
+		op, ok := operations[key]
+		if !ok {
+			op = &operation{
+				method:   method,
+				path:     path,
+				params:   params,
+				servers:  make(map[string]bool),
+				query:    make(map[string]*queryParam),
+				statuses: make(map[int]*Schema),
+			}
+			operations[key] = op
+			order = append(order, key)
+		}
+		op.servers[server] = true
+		op.add(entry)
+	}
+
+	operationIDs := make(map[string]bool, len(order))
+	for _, key := range order {
+		op := operations[key]
+		operation := op.operation()
+
+		// Different paths can name the same ID, as /users-list and
+		// /users/list do, but IDs must be unique within a document.
+		id := operation.OperationID
+		for n := 2; operationIDs[id]; n++ {
+			id = operation.OperationID + strconv.Itoa(n)
+		}
+		operationIDs[id] = true
+		operation.OperationID = id
+
+		if len(servers) > 1 {
+			for server := range op.servers {
+				operation.Servers = append(operation.Servers, Server{URL: server})
+			}
+			sort.Slice(operation.Servers, func(i, j int) bool {
+				return operation.Servers[i].URL < operation.Servers[j].URL
+			})
+		}
+
+		if doc.Paths[op.path] == nil {
+			doc.Paths[op.path] = make(PathItem)
+		}
+		doc.Paths[op.path][op.method] = operation
+	}
+
+	sort.Slice(doc.Servers, func(i, j int) bool {
+		return doc.Servers[i].URL < doc.Servers[j].URL
+	})
+	return doc
+}
+
+func isJSONAPICall(entry har.Entry) bool {
+	if entry.Request.URL.Scheme != "http" && entry.Request.URL.Scheme != "https" {
+		return false
+	}
+	if isJSON(entry.Response.Content.MIMEType) {
+		return true
+	}
+	return entry.Request.PostData != nil && isJSON(entry.Request.PostData.MIMEType)
+}
+
+func isJSON(mimeType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0]))
+	return mediaType == mediaTypeJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+func (op *operation) add(entry har.Entry) {
+	op.samples++
+
+	for name, values := range entry.Request.URL.Query() {
+		param, ok := op.query[name]
+		if !ok {
+			param = &queryParam{}
+			op.query[name] = param
+		}
+		param.count++
+		param.schema = mergeSchemas(param.schema, scalarSchema(values[0]))
+	}
+
+	if postData := entry.Request.PostData; postData != nil && postData.Text != "" {
+		op.bodies++
+		if v, ok := parseJSON(postData.Text); ok {
+			op.requests++
+			op.request = mergeSchemas(op.request, schemaOf(v))
+		}
+	}
+
+	status := entry.Response.Status
+	if status == 0 {
+		return
+	}
+	schema := op.statuses[status]
+	if body, err := chromedriver2har.ContentBody(entry.Response.Content); err == nil && isJSON(entry.Response.Content.MIMEType) {
+		if v, ok := parseJSON(string(body)); ok {
+			schema = mergeSchemas(schema, schemaOf(v))
+		}
+	}
+	op.statuses[status] = schema
+}
+
+func (op *operation) operation() *Operation {
+	operation := &Operation{
+		OperationID: operationID(op.method, op.path),
+		Parameters:  append([]Parameter(nil), op.params...),
+		Responses:   make(map[string]Response, len(op.statuses)),
+	}
+
+	names := make([]string, 0, len(op.query))
+	for name := range op.query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		param := op.query[name]
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:     name,
+			In:       "query",
+			Required: param.count == op.samples,
+			Schema:   param.schema,
+		})
+	}
+
+	if op.request != nil {
+		operation.RequestBody = &RequestBody{
+			Required: op.bodies == op.samples,
+			Content:  map[string]MediaType{mediaTypeJSON: {Schema: op.request}},
+		}
+	}
+
+	for status, schema := range op.statuses {
+		response := Response{Description: statusDescription(status)}
+		if schema != nil {
+			response.Content = map[string]MediaType{mediaTypeJSON: {Schema: schema}}
+		}
+		operation.Responses[strconv.Itoa(status)] = response
+	}
+	if len(operation.Responses) == 0 {
+		operation.Responses["default"] = Response{Description: "Response"}
+	}
+
+	return operation
+}
+
+// pathTemplate replaces numeric and UUID segments with parameters named after
+// the segment before them, so /users/42 becomes /users/{userId}.
+func pathTemplate(path string) (string, []Parameter) {
+	segments := strings.Split(path, "/")
+	var params []Parameter
+	names := make(map[string]bool)
+
+	for i, segment := range segments {
+		var schema *Schema
+		switch {
+		case numericSegment.MatchString(segment):
+			schema = &Schema{Type: typeInteger}
+		case uuidPattern.MatchString(segment):
+			schema = &Schema{Type: typeString, Format: formatUUID}
+		default:
+			continue
+		}
+
+		name := "id"
+		if i > 0 && segments[i-1] != "" && !strings.HasPrefix(segments[i-1], "{") {
+			name = lowerCamel(singular(segments[i-1])) + "Id"
+		}
+		unique := name
+		for n := 2; names[unique]; n++ {
+			unique = fmt.Sprintf("%s%d", name, n)
+		}
+		names[unique] = true
+
+		segments[i] = "{" + unique + "}"
+		params = append(params, Parameter{Name: unique, In: "path", Required: true, Schema: schema})
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+func singular(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "ses"), strings.HasSuffix(word, "xes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return strings.TrimSuffix(word, "s")
+	default:
+		return word
+	}
+}
+
+func lowerCamel(s string) string {
+	words := identifierNonWords.Split(s, -1)
+	var b strings.Builder
+	for _, word := range words {
+		if word == "" {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteString(strings.ToLower(word[:1]) + word[1:])
+		} else {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+// operationID names an operation after its method and path, such as
+// getUsersByUserId for GET /users/{userId}.
+func operationID(method, path string) string {
+	parts := []string{method}
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") {
+			parts = append(parts, "by", strings.Trim(segment, "{}"))
+		} else if segment != "" {
+			parts = append(parts, segment)
+		}
+	}
+	return lowerCamel(strings.Join(parts, " "))
+}
+
+func scalarSchema(value string) *Schema {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return &Schema{Type: typeInteger}
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return &Schema{Type: typeNumber}
+	}
+	if value == "true" || value == "false" {
+		return &Schema{Type: typeBoolean}
+	}
+	return &Schema{Type: typeString, Format: stringFormat(value)}
+}
+
+func statusDescription(status int) string {
+	if text := http.StatusText(status); text != "" {
+		return text
+	}
+	return "Response"
+}
@@ -0,0 +1,88 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/internal/hartest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfer(t *testing.T) {
+	jsonUTF8 := "application/json; charset=utf-8"
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{
+		hartest.NewEntry("GET", "https://api.example.com/v1/users/42?expand=true").Status(200).MIMEType(jsonUTF8).Body(`{"id":42,"name":"ada","email":null}`).Build(),
+		hartest.NewEntry("GET", "https://api.example.com/v1/users/7").Status(200).MIMEType(jsonUTF8).Body(`{"id":7,"name":"grace","score":1.5}`).Build(),
+		hartest.NewEntry("GET", "https://api.example.com/v1/users/8").Status(404).MIMEType(jsonUTF8).Body(`{"error":"not found"}`).Build(),
+		hartest.NewEntry("POST", "https://api.example.com/v1/categories/3f2504e0-4f89-11d3-9a0c-0305e82c3301/items").
+			PostData("application/json", `{"tags":["a","b"]}`).
+			Status(201).
+			MIMEType(jsonUTF8).
+			Body(`{"id":1,"created":"2024-01-02T03:04:05Z"}`).
+			Build(),
+		hartest.NewEntry("GET", "https://cdn.example.com/logo.png").Status(200).MIMEType("image/png").Body("").Build(),
+	}}}
+
+	doc := Infer(h, Options{Title: "Example"})
+	require.Equal(t, Version, doc.OpenAPI)
+	require.Equal(t, Info{Title: "Example", Version: defaultVersion}, doc.Info)
+	require.Equal(t, []Server{{URL: "https://api.example.com"}}, doc.Servers)
+	require.Len(t, doc.Paths, 2)
+	require.Nil(t, doc.Paths["/v1/users/{userId}"]["get"].Servers)
+
+	get := doc.Paths["/v1/users/{userId}"]["get"]
+	require.NotNil(t, get)
+	require.Equal(t, "getV1UsersByUserId", get.OperationID)
+	require.Equal(t, []Parameter{
+		{Name: "userId", In: "path", Required: true, Schema: &Schema{Type: typeInteger}},
+		{Name: "expand", In: "query", Required: false, Schema: &Schema{Type: typeBoolean}},
+	}, get.Parameters)
+
+	ok := get.Responses["200"].Content[mediaTypeJSON].Schema
+	require.Equal(t, typeObject, ok.Type)
+	require.Equal(t, []string{"id", "name"}, ok.Required)
+	require.Equal(t, &Schema{Type: typeInteger}, ok.Properties["id"])
+	require.True(t, ok.Properties["email"].Nullable)
+	require.Equal(t, typeNumber, ok.Properties["score"].Type)
+	require.Equal(t, "Not Found", get.Responses["404"].Description)
+
+	post := doc.Paths["/v1/categories/{categoryId}/items"]["post"]
+	require.NotNil(t, post)
+	require.Equal(t, &Schema{Type: typeString, Format: formatUUID}, post.Parameters[0].Schema)
+	require.True(t, post.RequestBody.Required)
+	tags := post.RequestBody.Content[mediaTypeJSON].Schema.Properties["tags"]
+	require.Equal(t, &Schema{Type: typeArray, Items: &Schema{Type: typeString}}, tags)
+	created := post.Responses["201"].Content[mediaTypeJSON].Schema.Properties["created"]
+	require.Equal(t, &Schema{Type: typeString, Format: formatDateTime}, created)
+
+	data, err := doc.YAML()
+	require.NoError(t, err)
+	require.Contains(t, string(data), "openapi: 3.0.3\ninfo:\n  title: Example\n")
+}
+
+func TestInferServersAndOperationIDs(t *testing.T) {
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{
+		hartest.NewEntry("GET", "https://api.example.com/users/list").Status(200).MIMEType(mediaTypeJSON).Body(`[]`).Build(),
+		hartest.NewEntry("GET", "https://api.example.com/users-list").Status(200).MIMEType(mediaTypeJSON).Body(`[]`).Build(),
+		hartest.NewEntry("GET", "https://admin.example.com/users/list").Status(200).MIMEType(mediaTypeJSON).Body(`[]`).Build(),
+		hartest.NewEntry("GET", "https://admin.example.com/audit").Status(200).MIMEType(mediaTypeJSON).Body(`[]`).Build(),
+	}}}
+
+	doc := Infer(h, Options{})
+	require.Equal(t, []Server{{URL: "https://admin.example.com"}, {URL: "https://api.example.com"}}, doc.Servers)
+	require.Equal(t, []Server{{URL: "https://admin.example.com"}, {URL: "https://api.example.com"}}, doc.Paths["/users/list"]["get"].Servers)
+	require.Equal(t, []Server{{URL: "https://api.example.com"}}, doc.Paths["/users-list"]["get"].Servers)
+	require.Equal(t, []Server{{URL: "https://admin.example.com"}}, doc.Paths["/audit"]["get"].Servers)
+
+	require.Equal(t, "getUsersList", doc.Paths["/users/list"]["get"].OperationID)
+	require.Equal(t, "getUsersList2", doc.Paths["/users-list"]["get"].OperationID)
+}
+
+func TestPathTemplate(t *testing.T) {
+	path, params := pathTemplate("/orders/12/lines/3/2")
+	require.Equal(t, "/orders/{orderId}/lines/{lineId}/{id}", path)
+	require.Len(t, params, 3)
+
+	path, _ = pathTemplate("/addresses/1/boxes/2")
+	require.Equal(t, "/addresses/{addressId}/boxes/{boxId}", path)
+}
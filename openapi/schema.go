@@ -0,0 +1,153 @@
+package openapi
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	typeArray   = "array"
+	typeBoolean = "boolean"
+	typeInteger = "integer"
+	typeNumber  = "number"
+	typeObject  = "object"
+	typeString  = "string"
+
+	formatDateTime = "date-time"
+	formatUUID     = "uuid"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func parseJSON(text string) (interface{}, bool) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+func schemaOf(v interface{}) *Schema {
+	switch v := v.(type) {
+	case nil:
+		return &Schema{Nullable: true}
+	case bool:
+		return &Schema{Type: typeBoolean}
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return &Schema{Type: typeInteger}
+		}
+		return &Schema{Type: typeNumber}
+	case string:
+		return &Schema{Type: typeString, Format: stringFormat(v)}
+	case []interface{}:
+		var items *Schema
+		for _, item := range v {
+			items = mergeSchemas(items, schemaOf(item))
+		}
+		if items == nil {
+			items = &Schema{}
+		}
+		return &Schema{Type: typeArray, Items: items}
+	case map[string]interface{}:
+		s := &Schema{Type: typeObject, Properties: make(map[string]*Schema, len(v))}
+		for name, value := range v {
+			s.Properties[name] = schemaOf(value)
+			s.Required = append(s.Required, name)
+		}
+		sort.Strings(s.Required)
+		return s
+	default:
+		return &Schema{}
+	}
+}
+
+func stringFormat(s string) string {
+	if uuidPattern.MatchString(s) {
+		return formatUUID
+	}
+	if _, err := time.Parse(time.RFC3339, s); err == nil {
+		return formatDateTime
+	}
+	return ""
+}
+
+// mergeSchemas combines the schemas of two samples of the same value. Object
+// properties are only required if every sample has them, and conflicting
+// types leave the type unconstrained.
+func mergeSchemas(a, b *Schema) *Schema {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case isNullOnly(a):
+		merged := *b
+		merged.Nullable = true
+		return &merged
+	case isNullOnly(b):
+		return mergeSchemas(b, a)
+	}
+
+	merged := &Schema{Nullable: a.Nullable || b.Nullable}
+	if a.Type == "" || b.Type == "" || a.Type != b.Type {
+		if isNumeric(a.Type) && isNumeric(b.Type) {
+			merged.Type = typeNumber
+		} else {
+			merged.unconstrained = true
+		}
+		return merged
+	}
+
+	merged.Type = a.Type
+	if a.Format == b.Format {
+		merged.Format = a.Format
+	}
+
+	switch a.Type {
+	case typeArray:
+		merged.Items = mergeSchemas(a.Items, b.Items)
+	case typeObject:
+		merged.Properties = make(map[string]*Schema)
+		for name, schema := range a.Properties {
+			merged.Properties[name] = mergeSchemas(schema, b.Properties[name])
+		}
+		for name, schema := range b.Properties {
+			if _, ok := merged.Properties[name]; !ok {
+				merged.Properties[name] = schema
+			}
+		}
+		merged.Required = intersect(a.Required, b.Required)
+	}
+	return merged
+}
+
+// isNullOnly reports whether a schema was only ever seen as null.
+func isNullOnly(s *Schema) bool {
+	return s.Nullable && s.Type == "" && !s.unconstrained
+}
+
+func isNumeric(t string) bool {
+	return t == typeInteger || t == typeNumber
+}
+
+func intersect(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+	}
+
+	var both []string
+	for _, s := range a {
+		if inB[s] {
+			both = append(both, s)
+		}
+	}
+	return both
+}
@@ -0,0 +1,85 @@
+package chromedriver2har
+
+import (
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/redact"
+	"github.com/pkg/errors"
+)
+
+type Option func(*options)
+
+type options struct {
+	entryFilters []func(har.Entry) bool
+	bodies       BodyProvider
+	redactor     *redact.Redactor
+	warn         func(error)
+}
+
+// BodyProvider looks up response bodies by DevTools request ID. The
+// performance log never carries bodies, so they have to be fetched from
+// the browser separately, e.g. with Network.getResponseBody.
+type BodyProvider interface {
+	ResponseBody(requestID string) (NetworkGetResponseBody, bool)
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func WithEntryFilter(keep func(har.Entry) bool) Option {
+	return func(o *options) {
+		o.entryFilters = append(o.entryFilters, keep)
+	}
+}
+
+// WithBodyProvider fills each entry's response content text from bodies.
+func WithBodyProvider(bodies BodyProvider) Option {
+	return func(o *options) {
+		o.bodies = bodies
+	}
+}
+
+// WithRedactor redacts the HAR before it's returned.
+func WithRedactor(redactor *redact.Redactor) Option {
+	return func(o *options) {
+		o.redactor = redactor
+	}
+}
+
+// WithWarnings reports problems that don't stop the conversion, such as
+// events dropped because their request was never sent, to warn.
+func WithWarnings(warn func(error)) Option {
+	return func(o *options) {
+		o.warn = warn
+	}
+}
+
+func (o options) warnf(format string, args ...interface{}) {
+	if o.warn != nil {
+		o.warn(errors.Errorf(format, args...))
+	}
+}
+
+func (o options) responseBody(requestID string) (NetworkGetResponseBody, bool) {
+	if o.bodies == nil {
+		return NetworkGetResponseBody{}, false
+	}
+	return o.bodies.ResponseBody(requestID)
+}
+
+func (o options) filtered() bool {
+	return len(o.entryFilters) > 0
+}
+
+func (o options) keep(entry har.Entry) bool {
+	for _, keep := range o.entryFilters {
+		if !keep(entry) {
+			return false
+		}
+	}
+	return true
+}
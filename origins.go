@@ -0,0 +1,129 @@
+package chromedriver2har
+
+import (
+	"encoding/json"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+)
+
+const (
+	MethodTargetAttachedToTarget  = "Target.attachedToTarget"
+	MethodTargetTargetCreated     = "Target.targetCreated"
+	MethodTargetTargetInfoChanged = "Target.targetInfoChanged"
+)
+
+const (
+	FetchOriginNetwork       = "network"
+	FetchOriginServiceWorker = "service-worker"
+	FetchOriginPreloadCache  = "preload-cache"
+	FetchOriginPrefetchCache = "prefetch-cache"
+	FetchOriginPush          = "push"
+	FetchOriginCache         = "cache"
+)
+
+const (
+	InitiatorPreload       = "preload"
+	InitiatorPrefetch      = "prefetch"
+	InitiatorServiceWorker = "service-worker"
+)
+
+const (
+	targetTypeServiceWorker = "service_worker"
+	resourceTypePrefetch    = "Prefetch"
+)
+
+func fetchOrigin(params *requestParams) string {
+	response := params.networkResponseReceived.Response
+
+	switch {
+	case response.Timing != nil && response.Timing.PushStart > 0:
+		return FetchOriginPush
+	case safeBoolDereference(response.FromServiceWorker):
+		return FetchOriginServiceWorker
+	case safeBoolDereference(response.FromPrefetchCache):
+		return FetchOriginPrefetchCache
+	case params.servedFromCache || safeBoolDereference(response.FromDiskCache):
+		if params.preload() {
+			return FetchOriginPreloadCache
+		}
+		return FetchOriginCache
+	default:
+		return FetchOriginNetwork
+	}
+}
+
+func initiator(params *requestParams) string {
+	switch {
+	case params.preload():
+		return InitiatorPreload
+	case params.resourceType() == resourceTypePrefetch:
+		return InitiatorPrefetch
+	default:
+		return params.networkRequestWillBeSent.Initiator.Type
+	}
+}
+
+func (rp *requestParams) preload() bool {
+	return safeBoolDereference(rp.networkRequestWillBeSent.Request.IsLinkPreload) || rp.networkRequestWillBeSent.Initiator.Type == InitiatorPreload
+}
+
+func (rp *requestParams) resourceType() string {
+	if rp.networkResponseReceived.Type != "" {
+		return rp.networkResponseReceived.Type
+	}
+	return rp.networkRequestWillBeSent.Type
+}
+
+func targetTypes(events []Event) map[string]string {
+	targetTypes := make(map[string]string)
+	for _, event := range events {
+		switch event.Method {
+		case MethodTargetAttachedToTarget, MethodTargetTargetCreated, MethodTargetTargetInfoChanged:
+		default:
+			continue
+		}
+
+		var data TargetAttachedToTarget
+		if err := json.Unmarshal(event.Params, &data); err != nil {
+			continue
+		}
+
+		if data.TargetInfo.TargetID != "" {
+			targetTypes[data.TargetInfo.TargetID] = data.TargetInfo.Type
+		}
+		if data.SessionID != "" {
+			targetTypes[data.SessionID] = data.TargetInfo.Type
+		}
+	}
+	return targetTypes
+}
+
+func correlateServiceWorkerEntries(pageEntries, serviceWorkerEntries []har.Entry) {
+	matched := make(map[int]bool, len(serviceWorkerEntries))
+
+	for i := range pageEntries {
+		pageEntry := &pageEntries[i]
+		if pageEntry.Custom[CustomFetchOrigin] != FetchOriginServiceWorker {
+			continue
+		}
+
+		start := pageEntry.StartedDateTime.Time
+		end := start.Add(durationMilliseconds(pageEntry.Time))
+
+		for j := range serviceWorkerEntries {
+			serviceWorkerEntry := &serviceWorkerEntries[j]
+			if matched[j] ||
+				serviceWorkerEntry.Request.Method != pageEntry.Request.Method ||
+				serviceWorkerEntry.Request.URL.String() != pageEntry.Request.URL.String() ||
+				serviceWorkerEntry.StartedDateTime.Before(start) ||
+				serviceWorkerEntry.StartedDateTime.After(end) {
+				continue
+			}
+
+			matched[j] = true
+			pageEntry.Custom[CustomServiceWorkerRequestID] = serviceWorkerEntry.Custom[CustomRequestID]
+			serviceWorkerEntry.Custom[CustomSatisfiedRequestID] = pageEntry.Custom[CustomRequestID]
+			break
+		}
+	}
+}
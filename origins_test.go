@@ -0,0 +1,44 @@
+package chromedriver2har
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceWorkerAttribution(t *testing.T) {
+	pageEvents := requestEvents("page", "1.1", "https://example.com/data.json", 10)
+	pageEvents[1].Params = json.RawMessage(strings.Replace(string(pageEvents[1].Params), `"status":200`, `"status":200,"fromServiceWorker":true`, 1))
+
+	preloadEvents := requestEvents("page", "1.2", "https://example.com/font.woff2", 10.1)
+	preloadEvents[0].Params = json.RawMessage(strings.Replace(string(preloadEvents[0].Params), `"method":"GET"`, `"method":"GET","isLinkPreload":true`, 1))
+	preloadEvents = append(preloadEvents, Event{Method: MethodNetworkRequestServedFromCache, Params: json.RawMessage(`{"requestId":"1.2"}`), TargetID: "page"})
+
+	events := append(pageEvents, preloadEvents...)
+	events = append(events, Event{
+		Method:   MethodTargetAttachedToTarget,
+		Params:   json.RawMessage(`{"sessionId":"S1","targetInfo":{"targetId":"sw","type":"service_worker","url":"https://example.com/sw.js"}}`),
+		TargetID: "page",
+	})
+	events = append(events, requestEvents("sw", "2.1", "https://example.com/data.json", 10.005)...)
+
+	h, err := FromEventSource(NewSliceSource(events))
+	require.NoError(t, err)
+	require.Len(t, h.Log.Pages, 1)
+	require.Len(t, h.Log.Entries, 3)
+
+	byRequestID := make(map[string]map[string]interface{})
+	for _, entry := range h.Log.Entries {
+		byRequestID[entry.Custom.String(CustomRequestID)] = entry.Custom
+	}
+
+	require.Equal(t, FetchOriginServiceWorker, byRequestID["1.1"][CustomFetchOrigin])
+	require.Equal(t, "2.1", byRequestID["1.1"][CustomServiceWorkerRequestID])
+	require.Equal(t, FetchOriginNetwork, byRequestID["2.1"][CustomFetchOrigin])
+	require.Equal(t, InitiatorServiceWorker, byRequestID["2.1"][CustomInitiator])
+	require.Equal(t, "1.1", byRequestID["2.1"][CustomSatisfiedRequestID])
+	require.Equal(t, FetchOriginPreloadCache, byRequestID["1.2"][CustomFetchOrigin])
+	require.Equal(t, InitiatorPreload, byRequestID["1.2"][CustomInitiator])
+}
@@ -3,7 +3,7 @@ package chromedriver2har
 import (
 	"encoding/json"
 
-	"github.com/jordanpotter/har"
+	"github.com/jordanpotter/chromedriver2har/har"
 	"github.com/pkg/errors"
 )
 
@@ -13,75 +13,138 @@ const (
 )
 
 type pageParams struct {
-	firstNetworkRequestWillBeSent NetworkRequestWillBeSent
-	pageDOMContentEventFired      PageDOMContentEventFired
-	pageLoadEventFired            PageLoadEventFired
+	firstNetworkRequestWillBeSent *NetworkRequestWillBeSent
+	pageDOMContentEventFired      *PageDOMContentEventFired
+	pageLoadEventFired            *PageLoadEventFired
+	paints                        map[string]float64
 }
 
-func harPage(chromeLogEntries []ChromeLogEntry) (har.Page, error) {
-	params, err := pageParams(chromeLogEntries)
+func harPage(id string, events []Event) (har.Page, error) {
+	params, err := paramsForPage(events)
 	if err != nil {
 		return har.Page{}, errors.Wrap(err, "failed to parse page params")
 	}
 
+	if params.firstNetworkRequestWillBeSent == nil {
+		return har.Page{}, errors.New("missing first request for page")
+	}
+
 	return har.Page{
-		//			StartedDateTime: TODO,
-		ID:    "page_1",
-		Title: params.firstNetworkRequestWillBeSent.DocumentURL,
-		PageTimings: har.PageTimings{
-			OnContentLoad: params.pageDOMContentEventFired.Timestamp,
-			OnLoad:        params.pageLoadEventFired.Timestamp,
-		},
+		StartedDateTime: harTime(params.firstNetworkRequestWillBeSent.WallTime),
+		ID:              id,
+		Title:           params.firstNetworkRequestWillBeSent.DocumentURL,
+		PageTimings:     harPageTimings(params),
+		Custom:          harPageCustom(params),
 	}, nil
 }
 
-func pageParams(chromeLogEntries []ChromeLogEntry) (*pageParams, error) {
-	params := pageParams{}
+func harPageTimings(params *pageParams) har.PageTimings {
+	start := params.firstNetworkRequestWillBeSent.Timestamp
+
+	var pageTimings har.PageTimings
+	if params.pageDOMContentEventFired != nil {
+		onContentLoad := (params.pageDOMContentEventFired.Timestamp - start) * 1000
+		pageTimings.OnContentLoad = &onContentLoad
+	}
+	if params.pageLoadEventFired != nil {
+		onLoad := (params.pageLoadEventFired.Timestamp - start) * 1000
+		pageTimings.OnLoad = &onLoad
+	}
+	return pageTimings
+}
+
+// harPageCustom reports paint markers from traces in milliseconds since the
+// page started, like the page timings.
+func harPageCustom(params *pageParams) har.Custom {
+	if len(params.paints) == 0 {
+		return nil
+	}
+
+	start := params.firstNetworkRequestWillBeSent.Timestamp
+	custom := make(har.Custom, len(params.paints))
+	for name, timestamp := range params.paints {
+		custom[name] = (timestamp - start) * 1000
+	}
+	return custom
+}
 
-	for _, chromeLogEntry := range chromeLogEntries {
+func paramsForPage(events []Event) (*pageParams, error) {
+	params := &pageParams{paints: make(map[string]float64)}
+
+	for _, event := range events {
 		var err error
 
-		switch chromeLogEntry.Message.Method {
+		switch event.Method {
 		case MethodNetworkRequestWillBeSent:
-			// TODO
+			err = processPageNetworkRequestWillBeSent(params, event.Params)
 		case MethodPageDOMContentEventFired:
-			err = processPageDOMContentEventFired(&params, chromeLogEntry.Message.Params)
+			err = processPageDOMContentEventFired(params, event.Params)
 		case MethodPageLoadEventFired:
-			err = processPageLoadEventFired(&params, chromeLogEntry.Message.Params)
+			err = processPageLoadEventFired(params, event.Params)
+		case methodTracePaint:
+			err = processPageTracePaint(params, event.Params)
 		}
 
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to parse entry %q", chromeLogEntry.Message.Method)
+			return nil, errors.Wrapf(err, "failed to parse entry %q", event.Method)
 		}
 	}
 
 	return params, nil
 }
 
-func processPageDOMContentEventFired(params *pageParams, params json.RawMessage) error {
+func processPageNetworkRequestWillBeSent(page *pageParams, params json.RawMessage) error {
+	var data NetworkRequestWillBeSent
+	if err := json.Unmarshal(params, &data); err != nil {
+		return errors.Wrap(err, "failed to unmarshal NetworkRequestWillBeSent data")
+	}
+
+	if page.firstNetworkRequestWillBeSent == nil {
+		page.firstNetworkRequestWillBeSent = &data
+	}
+	return nil
+}
+
+func processPageDOMContentEventFired(page *pageParams, params json.RawMessage) error {
 	var data PageDOMContentEventFired
 	if err := json.Unmarshal(params, &data); err != nil {
 		return errors.Wrap(err, "failed to unmarshal PageDOMContentEventFired data")
 	}
 
-	if params.pageDOMContentEventFired.Timestamp != 0 {
+	if page.pageDOMContentEventFired != nil {
 		return errors.New("already processed PageDOMContentEventFired")
 	}
 
-	params.pageDOMContentEventFired = data
+	page.pageDOMContentEventFired = &data
 	return nil
 }
 
-func processPageLoadEventFired(params *pageParams, params json.RawMessage) error {
+func processPageLoadEventFired(page *pageParams, params json.RawMessage) error {
 	var data PageLoadEventFired
 	if err := json.Unmarshal(params, &data); err != nil {
 		return errors.Wrap(err, "failed to unmarshal PageLoadEventFired data")
 	}
 
-	if params.pageLoadEventFired.Timestamp != 0 {
+	if page.pageLoadEventFired != nil {
 		return errors.New("already processed PageLoadEventFired")
 	}
 
-	params.pageLoadEventFired = data
+	page.pageLoadEventFired = &data
+	return nil
+}
+
+func processPageTracePaint(page *pageParams, params json.RawMessage) error {
+	var data TracePaint
+	if err := json.Unmarshal(params, &data); err != nil {
+		return errors.Wrap(err, "failed to unmarshal TracePaint data")
+	}
+
+	// Chrome reports a new largest contentful paint candidate whenever a
+	// larger element renders, so the last one wins.
+	if _, ok := page.paints[data.Name]; ok && data.Name != CustomLargestContentfulPaint {
+		return nil
+	}
+
+	page.paints[data.Name] = data.Timestamp
 	return nil
 }
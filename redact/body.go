@@ -0,0 +1,72 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+func (r *Redactor) body(text string) string {
+	if len(r.bodyPaths) > 0 {
+		trimmed := strings.TrimSpace(text)
+		if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			if redacted, ok := r.jsonBody(text); ok {
+				text = redacted
+			}
+		}
+	}
+	return r.text(text)
+}
+
+func (r *Redactor) jsonBody(text string) (string, bool) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", false
+	}
+
+	changed := false
+	for _, path := range r.bodyPaths {
+		value = r.jsonPath(value, path, path, &changed)
+	}
+	if !changed {
+		return text, true
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", false
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), true
+}
+
+func (r *Redactor) jsonPath(value interface{}, path, fullPath []string, changed *bool) interface{} {
+	if len(path) == 0 {
+		*changed = true
+		r.record("body paths", strings.Join(fullPath, "."))
+		return r.Placeholder(fmt.Sprint(value))
+	}
+
+	segment, rest := path[0], path[1:]
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if segment == wildcard || segment == key {
+				v[key] = r.jsonPath(child, rest, fullPath, changed)
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			if segment == wildcard || segment == fmt.Sprint(i) {
+				v[i] = r.jsonPath(child, rest, fullPath, changed)
+			}
+		}
+	}
+	return value
+}
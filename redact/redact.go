@@ -0,0 +1,370 @@
+package redact
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/pkg/errors"
+)
+
+const (
+	placeholderPrefix = "redacted-"
+	placeholderLength = 12
+	saltLength        = 16
+	wildcard          = "*"
+)
+
+// urlHeaders hold URLs whose query strings need the same treatment as the
+// request URL, including the HTTP/2 :path pseudo-header.
+var urlHeaders = map[string]bool{
+	":path":            true,
+	"content-location": true,
+	"location":         true,
+	"origin":           true,
+	"referer":          true,
+}
+
+type Config struct {
+	Headers     []string `json:"headers"`
+	Cookies     []string `json:"cookies"`
+	QueryParams []string `json:"queryParams"`
+	BodyPaths   []string `json:"bodyPaths"`
+	Patterns    []string `json:"patterns"`
+	// Salt is hashed with each redacted value, so placeholders of guessable
+	// values can't be reversed by hashing guesses. A random salt is used when
+	// it's empty; set one to get the same placeholders across runs.
+	Salt string `json:"salt"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Headers: []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-CSRF-Token", "X-XSRF-Token"},
+		Cookies: []string{wildcard},
+	}
+}
+
+type Redactor struct {
+	config      Config
+	headers     map[string]bool
+	cookies     map[string]bool
+	queryParams map[string]bool
+	bodyPaths   [][]string
+	patterns    []*regexp.Regexp
+
+	redacted map[string]map[string]bool
+}
+
+func New(config Config) (*Redactor, error) {
+	if config.Salt == "" {
+		salt := make([]byte, saltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, errors.Wrap(err, "failed to generate salt")
+		}
+		config.Salt = hex.EncodeToString(salt)
+	}
+
+	r := &Redactor{
+		config:      config,
+		headers:     lowerSet(config.Headers),
+		cookies:     set(config.Cookies),
+		queryParams: set(config.QueryParams),
+		redacted:    make(map[string]map[string]bool),
+	}
+
+	for _, path := range config.BodyPaths {
+		r.bodyPaths = append(r.bodyPaths, strings.Split(path, "."))
+	}
+
+	for _, pattern := range config.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compile pattern %q", pattern)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	return r, nil
+}
+
+func (r *Redactor) Placeholder(value string) string {
+	sum := sha256.Sum256([]byte(r.config.Salt + value))
+	return placeholderPrefix + hex.EncodeToString(sum[:])[:placeholderLength]
+}
+
+func (r *Redactor) Redact(h *har.HAR) {
+	r.redacted = make(map[string]map[string]bool)
+
+	for i := range h.Log.Entries {
+		r.entry(&h.Log.Entries[i])
+	}
+
+	for i := range h.Log.Pages {
+		page := &h.Log.Pages[i]
+		page.Title = r.urlText(page.Title)
+		r.custom(page.Custom)
+	}
+
+	if summary := r.summary(); summary != "" {
+		comment := summary
+		if h.Log.Comment != nil && *h.Log.Comment != "" {
+			comment = *h.Log.Comment + "\n" + summary
+		}
+		h.Log.Comment = &comment
+	}
+}
+
+func (r *Redactor) entry(entry *har.Entry) {
+	request := &entry.Request
+	request.URL = r.url(request.URL)
+	request.Headers = r.headerList(request.Headers)
+	request.Cookies = r.cookieList(request.Cookies)
+	for i := range request.QueryString {
+		param := &request.QueryString[i]
+		param.Value = r.queryParam(param.Name, param.Value)
+	}
+	if request.PostData != nil {
+		r.postData(request.PostData)
+	}
+
+	response := &entry.Response
+	response.Headers = r.headerList(response.Headers)
+	response.Cookies = r.cookieList(response.Cookies)
+	response.RedirectURL = r.url(response.RedirectURL)
+	if response.Content.Text != nil && (response.Content.Encoding == nil || *response.Content.Encoding == "") {
+		text := r.body(*response.Content.Text)
+		response.Content.Text = &text
+	}
+
+	r.custom(entry.Custom)
+}
+
+// custom redacts string-valued custom fields, several of which (such as
+// _documentURL) hold URLs.
+func (r *Redactor) custom(custom har.Custom) {
+	for key, value := range custom {
+		if s, ok := value.(string); ok {
+			custom[key] = r.urlText(s)
+		}
+	}
+}
+
+func (r *Redactor) url(u har.URL) har.URL {
+	u.RawQuery = r.rawQuery(u.RawQuery)
+
+	if len(r.patterns) == 0 {
+		return u
+	}
+
+	parsed, err := url.Parse(r.text(u.String()))
+	if err != nil {
+		return u
+	}
+	return har.URL{URL: *parsed}
+}
+
+// rawQuery replaces the values of configured query params and leaves every
+// other parameter as it was, so redacted requests still replay and export
+// byte for byte.
+func (r *Redactor) rawQuery(rawQuery string) string {
+	if rawQuery == "" || len(r.queryParams) == 0 {
+		return rawQuery
+	}
+
+	return replacePairs(rawQuery, func(name, value string) string {
+		if !r.redactsQueryParam(name) {
+			return value
+		}
+		return r.queryParam(name, value)
+	})
+}
+
+// replacePairs rewrites the values of the &-separated name=value pairs in
+// encoded that redact changes. Every other pair, and the pair order, are
+// kept as they were.
+func replacePairs(encoded string, redact func(name, value string) string) string {
+	pairs := strings.Split(encoded, "&")
+	for i, pair := range pairs {
+		components := strings.SplitN(pair, "=", 2)
+		name, err := url.QueryUnescape(components[0])
+		if err != nil {
+			continue
+		}
+
+		value := ""
+		if len(components) == 2 {
+			if value, err = url.QueryUnescape(components[1]); err != nil {
+				value = components[1]
+			}
+		}
+		if redacted := redact(name, value); redacted != value {
+			pairs[i] = components[0] + "=" + url.QueryEscape(redacted)
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// urlText redacts text that may be a URL, such as a Referer header or a
+// page title, and falls back to the plain text patterns otherwise.
+func (r *Redactor) urlText(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || (u.Host == "" && !strings.HasPrefix(s, "/")) {
+		return r.text(s)
+	}
+
+	redacted := r.url(har.URL{URL: *u})
+	return redacted.String()
+}
+
+func (r *Redactor) headerList(headers []har.Header) []har.Header {
+	for i := range headers {
+		header := &headers[i]
+		name := strings.ToLower(header.Name)
+
+		switch {
+		case r.headers[name]:
+			r.record("headers", header.Name)
+			header.Value = r.Placeholder(header.Value)
+		case name == "cookie":
+			header.Value = r.cookieHeader(header.Value)
+		case name == "set-cookie":
+			header.Value = r.setCookieHeader(header.Value)
+		case urlHeaders[name]:
+			header.Value = r.urlText(header.Value)
+		default:
+			header.Value = r.text(header.Value)
+		}
+	}
+	return headers
+}
+
+func (r *Redactor) cookieList(cookies []har.Cookie) []har.Cookie {
+	for i := range cookies {
+		cookies[i].Value = r.cookie(cookies[i].Name, cookies[i].Value)
+	}
+	return cookies
+}
+
+func (r *Redactor) cookie(name, value string) string {
+	if r.cookies[wildcard] || r.cookies[name] {
+		r.record("cookies", name)
+		return r.Placeholder(value)
+	}
+	return r.text(value)
+}
+
+func (r *Redactor) cookieHeader(value string) string {
+	cookies := strings.Split(value, ";")
+	for i, cookie := range cookies {
+		components := strings.SplitN(strings.TrimSpace(cookie), "=", 2)
+		if len(components) == 2 {
+			cookies[i] = components[0] + "=" + r.cookie(components[0], components[1])
+		}
+	}
+	return strings.Join(cookies, "; ")
+}
+
+func (r *Redactor) setCookieHeader(value string) string {
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		attributes := strings.SplitN(line, ";", 2)
+		components := strings.SplitN(strings.TrimSpace(attributes[0]), "=", 2)
+		if len(components) != 2 {
+			continue
+		}
+		attributes[0] = components[0] + "=" + r.cookie(components[0], components[1])
+		lines[i] = strings.Join(attributes, ";")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *Redactor) redactsQueryParam(name string) bool {
+	return r.queryParams[wildcard] || r.queryParams[name]
+}
+
+func (r *Redactor) queryParam(name, value string) string {
+	if r.redactsQueryParam(name) {
+		r.record("query params", name)
+		return r.Placeholder(value)
+	}
+	return r.text(value)
+}
+
+func (r *Redactor) postData(postData *har.PostData) {
+	for i := range postData.Params {
+		param := &postData.Params[i]
+		if param.Value != nil {
+			value := r.queryParam(param.Name, *param.Value)
+			param.Value = &value
+		}
+	}
+
+	if strings.Contains(postData.MIMEType, "application/x-www-form-urlencoded") {
+		postData.Text = replacePairs(postData.Text, r.queryParam)
+		return
+	}
+
+	postData.Text = r.body(postData.Text)
+}
+
+func (r *Redactor) text(s string) string {
+	for _, re := range r.patterns {
+		s = re.ReplaceAllStringFunc(s, func(match string) string {
+			r.record("patterns", re.String())
+			return r.Placeholder(match)
+		})
+	}
+	return s
+}
+
+func (r *Redactor) record(category, name string) {
+	if r.redacted[category] == nil {
+		r.redacted[category] = make(map[string]bool)
+	}
+	r.redacted[category][name] = true
+}
+
+func (r *Redactor) summary() string {
+	categories := make([]string, 0, len(r.redacted))
+	for category := range r.redacted {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	parts := make([]string, 0, len(categories))
+	for _, category := range categories {
+		names := make([]string, 0, len(r.redacted[category]))
+		for name := range r.redacted[category] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		parts = append(parts, fmt.Sprintf("%s (%s)", category, strings.Join(names, ", ")))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return "redacted " + strings.Join(parts, "; ")
+}
+
+func set(values []string) map[string]bool {
+	s := make(map[string]bool, len(values))
+	for _, value := range values {
+		s[value] = true
+	}
+	return s
+}
+
+func lowerSet(values []string) map[string]bool {
+	s := make(map[string]bool, len(values))
+	for _, value := range values {
+		s[strings.ToLower(value)] = true
+	}
+	return s
+}
@@ -0,0 +1,141 @@
+package redact
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	u, err := url.Parse("https://example.com/api?token=abc&page=2")
+	require.NoError(t, err)
+	body := `{"user":{"name":"ada","password":"hunter2"},"items":[{"secret":1},{"secret":2}]}`
+
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{{
+		Request: har.Request{
+			URL: har.URL{URL: *u},
+			Headers: []har.Header{
+				{Name: "authorization", Value: "Bearer abc"},
+				{Name: "Cookie", Value: "session=s1; theme=dark"},
+				{Name: "X-Email", Value: "ada@example.com"},
+			},
+			Cookies:     []har.Cookie{{Name: "session", Value: "s1"}, {Name: "theme", Value: "dark"}},
+			QueryString: []har.QueryStringParam{{Name: "token", Value: "abc"}, {Name: "page", Value: "2"}},
+			PostData:    &har.PostData{MIMEType: "application/json", Text: body},
+		},
+	}}}}
+
+	redactor, err := New(Config{
+		Headers:     []string{"Authorization"},
+		Cookies:     []string{"session"},
+		QueryParams: []string{"token"},
+		BodyPaths:   []string{"user.password", "items.*.secret"},
+		Patterns:    []string{`[a-z]+@example\.com`},
+	})
+	require.NoError(t, err)
+	redactor.Redact(h)
+
+	request := h.Log.Entries[0].Request
+	require.Equal(t, redactor.Placeholder("Bearer abc"), request.Headers[0].Value)
+	require.Equal(t, "session="+redactor.Placeholder("s1")+"; theme=dark", request.Headers[1].Value)
+	require.Equal(t, redactor.Placeholder("ada@example.com"), request.Headers[2].Value)
+	require.Equal(t, redactor.Placeholder("s1"), request.Cookies[0].Value)
+	require.Equal(t, "dark", request.Cookies[1].Value)
+	require.Equal(t, redactor.Placeholder("abc"), request.QueryString[0].Value)
+	require.Equal(t, "2", request.URL.Query().Get("page"))
+	require.Equal(t, redactor.Placeholder("abc"), request.URL.Query().Get("token"))
+	require.JSONEq(t, `{"user":{"name":"ada","password":"`+redactor.Placeholder("hunter2")+`"},"items":[{"secret":"`+redactor.Placeholder("1")+`"},{"secret":"`+redactor.Placeholder("2")+`"}]}`, request.PostData.Text)
+
+	require.NotNil(t, h.Log.Comment)
+	require.Equal(t, "redacted body paths (items.*.secret, user.password); cookies (session); headers (authorization); patterns ([a-z]+@example\\.com); query params (token)", *h.Log.Comment)
+}
+
+func TestRedactURLs(t *testing.T) {
+	u, err := url.Parse("https://example.com/search?q=a+b&token=abc&z=%7E")
+	require.NoError(t, err)
+
+	h := &har.HAR{Log: har.Log{
+		Pages: []har.Page{{Title: "https://example.com/?token=abc"}},
+		Entries: []har.Entry{{
+			Request: har.Request{
+				URL:     har.URL{URL: *u},
+				Headers: []har.Header{{Name: "Referer", Value: "https://example.com/?token=abc"}},
+			},
+			Response: har.Response{
+				Headers: []har.Header{{Name: "Location", Value: "/next?token=abc"}},
+			},
+			Custom: har.Custom{"_documentURL": "https://example.com/?token=abc"},
+		}},
+	}}
+
+	redactor, err := New(Config{QueryParams: []string{"token"}})
+	require.NoError(t, err)
+	redactor.Redact(h)
+
+	placeholder := redactor.Placeholder("abc")
+	entry := h.Log.Entries[0]
+	require.Equal(t, "q=a+b&token="+placeholder+"&z=%7E", entry.Request.URL.RawQuery)
+	require.Equal(t, "https://example.com/?token="+placeholder, entry.Request.Headers[0].Value)
+	require.Equal(t, "/next?token="+placeholder, entry.Response.Headers[0].Value)
+	require.Equal(t, "https://example.com/?token="+placeholder, entry.Custom.String("_documentURL"))
+	require.Equal(t, "https://example.com/?token="+placeholder, h.Log.Pages[0].Title)
+	require.Equal(t, "redacted query params (token)", *h.Log.Comment)
+
+	clean := &har.HAR{Log: har.Log{Pages: []har.Page{{Title: "Example"}}}}
+	redactor.Redact(clean)
+	require.Nil(t, clean.Log.Comment)
+}
+
+func TestRedactHTTP2Path(t *testing.T) {
+	u, err := url.Parse("https://example.com/callback?code=abc&state=1")
+	require.NoError(t, err)
+
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{{
+		Request: har.Request{
+			URL:         har.URL{URL: *u},
+			HTTPVersion: "HTTP/2",
+			Headers: []har.Header{
+				{Name: ":authority", Value: "example.com"},
+				{Name: ":path", Value: "/callback?code=abc&state=1"},
+			},
+		},
+	}}}}
+
+	redactor, err := New(Config{QueryParams: []string{"code"}})
+	require.NoError(t, err)
+	redactor.Redact(h)
+
+	request := h.Log.Entries[0].Request
+	require.Equal(t, "/callback?code="+redactor.Placeholder("abc")+"&state=1", request.Headers[1].Value)
+	require.Equal(t, "code="+redactor.Placeholder("abc")+"&state=1", request.URL.RawQuery)
+}
+
+func TestRedactFormBody(t *testing.T) {
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{
+		{Request: har.Request{PostData: &har.PostData{MIMEType: "application/x-www-form-urlencoded", Text: "z=%7E&password=hunter2&a=1+2"}}},
+		{Request: har.Request{PostData: &har.PostData{MIMEType: "application/x-www-form-urlencoded", Text: "z=%7E&a=1+2"}}},
+	}}}
+
+	redactor, err := New(Config{QueryParams: []string{"password"}})
+	require.NoError(t, err)
+	redactor.Redact(h)
+
+	require.Equal(t, "z=%7E&password="+redactor.Placeholder("hunter2")+"&a=1+2", h.Log.Entries[0].Request.PostData.Text)
+	require.Equal(t, "z=%7E&a=1+2", h.Log.Entries[1].Request.PostData.Text)
+}
+
+func TestRedactSalt(t *testing.T) {
+	first, err := New(Config{})
+	require.NoError(t, err)
+	second, err := New(Config{})
+	require.NoError(t, err)
+	require.NotEqual(t, first.Placeholder("1234"), second.Placeholder("1234"))
+
+	salted, err := New(Config{Salt: "pepper"})
+	require.NoError(t, err)
+	again, err := New(Config{Salt: "pepper"})
+	require.NoError(t, err)
+	require.Equal(t, salted.Placeholder("1234"), again.Placeholder("1234"))
+}
@@ -0,0 +1,256 @@
+package replay
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	caCommonName     = "chromedriver2har replay CA"
+	certificateValid = 365 * 24 * time.Hour
+	handshakeTimeout = 10 * time.Second
+)
+
+// CACertificate returns the certificate that signs the certificates served
+// to CONNECT clients. Clients replaying HTTPS through the server must trust it.
+func (s *Server) CACertificate() (*x509.Certificate, error) {
+	ca, err := s.certificateAuthority()
+	if err != nil {
+		return nil, err
+	}
+	return ca.Leaf, nil
+}
+
+func (s *Server) certificateAuthority() (*tls.Certificate, error) {
+	s.caOnce.Do(func() {
+		if s.ca == nil {
+			s.ca, s.caErr = generateCertificateAuthority()
+		}
+	})
+	return s.ca, s.caErr
+}
+
+// connect terminates TLS for a CONNECT tunnel with a certificate for the
+// requested host, and serves the tunnelled requests as absolute-form requests
+// for that host.
+func (s *Server) connect(w http.ResponseWriter, r *http.Request) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "CONNECT is not supported by this connection", http.StatusInternalServerError)
+		return
+	}
+
+	conn, buffered, err := hijacker.Hijack()
+	if err != nil {
+		http.Error(w, "failed to hijack connection", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+		conn.Close()
+		return
+	}
+
+	target := r.Host
+	hostname, _, err := net.SplitHostPort(target)
+	if err != nil {
+		hostname = target
+	}
+
+	tlsConn := tls.Server(&bufferedConn{Conn: conn, reader: buffered.Reader}, &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return s.certificate(hostname)
+		},
+	})
+	tlsConn.SetDeadline(time.Now().Add(handshakeTimeout))
+	if err := tlsConn.Handshake(); err != nil {
+		tlsConn.Close()
+		return
+	}
+	tlsConn.SetDeadline(time.Time{})
+
+	tunnel := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.URL.Scheme = "https"
+		r.URL.Host = target
+		s.ServeHTTP(w, r)
+	})}
+	tunnel.Serve(newConnListener(tlsConn))
+}
+
+func (s *Server) certificate(hostname string) (*tls.Certificate, error) {
+	s.certsMu.Lock()
+	defer s.certsMu.Unlock()
+
+	if cert, ok := s.certs[hostname]; ok {
+		return cert, nil
+	}
+
+	template, err := certificateTemplate(hostname)
+	if err != nil {
+		return nil, err
+	}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	if ip := net.ParseIP(hostname); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{hostname}
+	}
+
+	ca, err := s.certificateAuthority()
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := newCertificate(template, ca)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create certificate for %q", hostname)
+	}
+	s.certs[hostname] = cert
+	return cert, nil
+}
+
+func generateCertificateAuthority() (*tls.Certificate, error) {
+	template, err := certificateTemplate(caCommonName)
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage |= x509.KeyUsageCertSign
+
+	ca, err := newCertificate(template, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA certificate")
+	}
+	return ca, nil
+}
+
+// withLeaf returns ca with its parsed certificate, which signing needs.
+func withLeaf(ca *tls.Certificate) (*tls.Certificate, error) {
+	if ca.Leaf != nil {
+		return ca, nil
+	}
+	if len(ca.Certificate) == 0 {
+		return nil, errors.New("CA has no certificate")
+	}
+	leaf, err := x509.ParseCertificate(ca.Certificate[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse CA certificate")
+	}
+	parsed := *ca
+	parsed.Leaf = leaf
+	return &parsed, nil
+}
+
+func certificateTemplate(commonName string) (*x509.Certificate, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate serial number")
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(certificateValid),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}, nil
+}
+
+// newCertificate signs template with parent, or self-signs it when parent is
+// nil.
+func newCertificate(template *x509.Certificate, parent *tls.Certificate) (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate key")
+	}
+
+	issuer, issuerKey := template, interface{}(key)
+	if parent != nil {
+		issuer, issuerKey = parent.Leaf, parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, issuerKey)
+	if err != nil {
+		return nil, err
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, nil
+}
+
+// bufferedConn reads whatever the client sent after CONNECT before the
+// connection was hijacked.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// connListener hands a single connection to an http.Server and stops it
+// once that connection is closed.
+type connListener struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	addr   net.Addr
+	once   sync.Once
+	closed chan struct{}
+}
+
+func newConnListener(conn net.Conn) *connListener {
+	l := &connListener{addr: conn.LocalAddr(), closed: make(chan struct{})}
+	l.conn = &listenerConn{Conn: conn, listener: l}
+	return l
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	l.mu.Lock()
+	conn := l.conn
+	l.conn = nil
+	l.mu.Unlock()
+
+	if conn != nil {
+		return conn, nil
+	}
+	<-l.closed
+	return nil, errors.New("tunnel closed")
+}
+
+func (l *connListener) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *connListener) Addr() net.Addr {
+	return l.addr
+}
+
+type listenerConn struct {
+	net.Conn
+	listener *connListener
+}
+
+func (c *listenerConn) Close() error {
+	c.listener.Close()
+	return c.Conn.Close()
+}
@@ -0,0 +1,311 @@
+package replay
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/pkg/errors"
+)
+
+type UnmatchedMode string
+
+const (
+	UnmatchedNotFound UnmatchedMode = "not-found"
+	UnmatchedError    UnmatchedMode = "error"
+	UnmatchedNearest  UnmatchedMode = "nearest"
+)
+
+type Options struct {
+	MatchBody bool
+	// IgnoreHost matches proxied requests on path and query only. Requests
+	// sent to the server directly always are, since they don't name a host.
+	IgnoreHost      bool
+	Unmatched       UnmatchedMode
+	SimulateLatency bool
+	// CA signs the certificates presented to clients tunnelling HTTPS
+	// through CONNECT. When it's nil, a CA is generated the first time one is
+	// needed, so servers that never see CONNECT don't pay for the key.
+	CA *tls.Certificate
+}
+
+type Server struct {
+	opts    Options
+	all     []har.Entry
+	entries map[string][]har.Entry
+
+	mu     sync.Mutex
+	served map[string]int
+
+	caOnce  sync.Once
+	ca      *tls.Certificate
+	caErr   error
+	certsMu sync.Mutex
+	certs   map[string]*tls.Certificate
+}
+
+func New(h *har.HAR, opts Options) (*Server, error) {
+	switch opts.Unmatched {
+	case "":
+		opts.Unmatched = UnmatchedNotFound
+	case UnmatchedNotFound, UnmatchedError, UnmatchedNearest:
+	default:
+		return nil, errors.Errorf("unknown unmatched mode %q", opts.Unmatched)
+	}
+
+	var ca *tls.Certificate
+	if opts.CA != nil {
+		var err error
+		if ca, err = withLeaf(opts.CA); err != nil {
+			return nil, err
+		}
+	}
+
+	s := &Server{
+		opts:    opts,
+		all:     h.Log.Entries,
+		entries: make(map[string][]har.Entry),
+		served:  make(map[string]int),
+		ca:      ca,
+		certs:   make(map[string]*tls.Certificate),
+	}
+	for _, entry := range h.Log.Entries {
+		u := &entry.Request.URL.URL
+		for _, host := range []string{"", hostKey(u)} {
+			key := key(entry.Request.Method, host, u)
+			s.entries[key] = append(s.entries[key], entry)
+		}
+	}
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		s.connect(w, r)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	entry, ok := s.match(r, string(body))
+	if !ok {
+		s.unmatched(w, r)
+		return
+	}
+
+	s.serve(w, r, entry)
+}
+
+func (s *Server) match(r *http.Request, body string) (har.Entry, bool) {
+	u := requestURL(r)
+	host := s.host(r)
+	key := key(r.Method, host, u)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	candidates := s.entries[key]
+	if s.opts.MatchBody {
+		candidates = matchingBodies(candidates, body)
+		key += "\n" + body
+	}
+
+	if len(candidates) == 0 {
+		if s.opts.Unmatched != UnmatchedNearest {
+			return har.Entry{}, false
+		}
+		return s.nearest(r.Method, host, u)
+	}
+
+	// Repeated requests are answered in recorded order, with the last
+	// recorded response repeated once they run out.
+	i := s.served[key]
+	if i >= len(candidates) {
+		i = len(candidates) - 1
+	}
+	s.served[key]++
+	return candidates[i], true
+}
+
+func (s *Server) nearest(method, host string, u *url.URL) (har.Entry, bool) {
+	var best har.Entry
+	bestScore := -1
+	for _, entry := range s.all {
+		if entry.Request.Method != method {
+			continue
+		}
+		if host != "" && hostKey(&entry.Request.URL.URL) != host {
+			continue
+		}
+		if score := similarity(&entry.Request.URL.URL, u); score > bestScore {
+			best, bestScore = entry, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+func (s *Server) unmatched(w http.ResponseWriter, r *http.Request) {
+	switch s.opts.Unmatched {
+	case UnmatchedError:
+		http.Error(w, fmt.Sprintf("no recorded response for %s %s and passthrough is disabled", r.Method, requestURL(r)), http.StatusBadGateway)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, entry har.Entry) {
+	response := entry.Response
+	if response.Status == 0 {
+		http.Error(w, "recorded request failed", http.StatusBadGateway)
+		return
+	}
+
+	body, err := chromedriver2har.ContentBody(response.Content)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode recorded body: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	firstByte, receive := latency(entry)
+	if s.opts.SimulateLatency && !sleep(r, firstByte) {
+		return
+	}
+
+	for name, values := range chromedriver2har.ResponseHeader(response) {
+		w.Header()[name] = values
+	}
+	w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+	w.WriteHeader(response.Status)
+
+	if s.opts.SimulateLatency && receive > 0 {
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		if !sleep(r, receive) {
+			return
+		}
+	}
+
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+}
+
+// host returns the host a request is matched on: only absolute-form
+// requests, sent to the server as a proxy, name one.
+func (s *Server) host(r *http.Request) string {
+	if s.opts.IgnoreHost || !r.URL.IsAbs() {
+		return ""
+	}
+	return hostKey(r.URL)
+}
+
+func key(method, host string, u *url.URL) string {
+	return method + " " + host + u.EscapedPath() + "?" + u.Query().Encode()
+}
+
+// hostKey is the scheme and lowercased host without the scheme's default
+// port, so tunnelled requests for example.com:443 match https://example.com/.
+func hostKey(u *url.URL) string {
+	scheme, host := strings.ToLower(u.Scheme), strings.ToLower(u.Host)
+	switch {
+	case scheme == "https":
+		host = strings.TrimSuffix(host, ":443")
+	case scheme == "http":
+		host = strings.TrimSuffix(host, ":80")
+	}
+	return scheme + "://" + host
+}
+
+func requestURL(r *http.Request) *url.URL {
+	u := *r.URL
+	if u.Host == "" {
+		u.Host = r.Host
+	}
+	return &u
+}
+
+func matchingBodies(entries []har.Entry, body string) []har.Entry {
+	matching := make([]har.Entry, 0, len(entries))
+	for _, entry := range entries {
+		recorded := ""
+		if entry.Request.PostData != nil {
+			recorded = entry.Request.PostData.Text
+		}
+		if recorded == body {
+			matching = append(matching, entry)
+		}
+	}
+	return matching
+}
+
+// similarity scores shared leading path segments above matching query
+// parameters, so /api/users/2 is nearer to /api/users/1 than /api/orders/2.
+func similarity(recorded, requested *url.URL) int {
+	score := 0
+
+	recordedSegments := strings.Split(strings.Trim(recorded.Path, "/"), "/")
+	requestedSegments := strings.Split(strings.Trim(requested.Path, "/"), "/")
+	for i := 0; i < len(recordedSegments) && i < len(requestedSegments); i++ {
+		if recordedSegments[i] != requestedSegments[i] {
+			break
+		}
+		score += 100
+	}
+	if len(recordedSegments) == len(requestedSegments) {
+		score += 10
+	}
+
+	requestedQuery := requested.Query()
+	for name, values := range recorded.Query() {
+		if requestedQuery.Get(name) == values[0] {
+			score++
+		}
+	}
+	return score
+}
+
+// latency splits the recorded time into the part before the first byte and the
+// time spent receiving the body.
+func latency(entry har.Entry) (time.Duration, time.Duration) {
+	receive := entry.Timings.Receive
+	if receive < 0 {
+		receive = 0
+	}
+	firstByte := entry.Time - receive
+	if firstByte < 0 {
+		firstByte = 0
+	}
+	return milliseconds(firstByte), milliseconds(receive)
+}
+
+func milliseconds(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+func sleep(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
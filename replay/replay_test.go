@@ -0,0 +1,161 @@
+package replay
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/internal/hartest"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, server *httptest.Server, method, path, body string) (int, string, http.Header) {
+	request, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	return response.StatusCode, string(data), response.Header
+}
+
+func TestServer(t *testing.T) {
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{
+		hartest.NewEntry("GET", "https://example.com/api/users/1?b=2&a=1").
+			Status(200).
+			ResponseHeader(":status", "200").
+			ResponseHeader("Content-Type", "application/json").
+			ResponseHeader("Content-Encoding", "gzip").
+			ResponseHeader("Set-Cookie", "a=1\nb=2").
+			Body(`{"id":1}`).
+			Build(),
+		hartest.NewEntry("GET", "https://example.com/poll").Status(200).Body("first").Build(),
+		hartest.NewEntry("GET", "https://example.com/poll").Status(200).Body("second").Build(),
+		hartest.NewEntry("POST", "https://example.com/api/login").PostData("", `{"user":"ada"}`).Status(201).Body("ada").Build(),
+		hartest.NewEntry("POST", "https://example.com/api/login").PostData("", `{"user":"bob"}`).Status(403).Body("bob").Build(),
+		hartest.NewEntry("GET", "https://example.com/logo.png").Status(200).Body("iVBORw0KGgo=").Encoding("base64").Build(),
+	}}}
+
+	replay, err := New(h, Options{MatchBody: true, IgnoreHost: true})
+	require.NoError(t, err)
+	server := httptest.NewServer(replay)
+	defer server.Close()
+
+	status, body, header := get(t, server, "GET", "/api/users/1?a=1&b=2", "")
+	require.Equal(t, 200, status)
+	require.Equal(t, `{"id":1}`, body)
+	require.Equal(t, "application/json", header.Get("Content-Type"))
+	require.Empty(t, header.Get("Content-Encoding"))
+	require.Equal(t, []string{"a=1", "b=2"}, header["Set-Cookie"])
+
+	for _, expected := range []string{"first", "second", "second"} {
+		_, body, _ = get(t, server, "GET", "/poll", "")
+		require.Equal(t, expected, body)
+	}
+
+	status, body, _ = get(t, server, "POST", "/api/login", `{"user":"bob"}`)
+	require.Equal(t, 403, status)
+	require.Equal(t, "bob", body)
+
+	_, body, _ = get(t, server, "GET", "/logo.png", "")
+	require.Equal(t, "\x89PNG\r\n\x1a\n", body)
+
+	status, _, _ = get(t, server, "GET", "/api/users/2", "")
+	require.Equal(t, http.StatusNotFound, status)
+
+	_, err = New(h, Options{Unmatched: "proxy"})
+	require.Error(t, err)
+}
+
+func TestServerUnmatched(t *testing.T) {
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{
+		hartest.NewEntry("GET", "https://example.com/api/orders/1").Status(200).Body("order").Time(30).Timings(har.Timings{Wait: 20, Receive: 10}).Build(),
+		hartest.NewEntry("GET", "https://example.com/api/users/1").Status(200).Body("user").Time(30).Timings(har.Timings{Wait: 20, Receive: 10}).Build(),
+	}}}
+
+	nearest, err := New(h, Options{IgnoreHost: true, Unmatched: UnmatchedNearest, SimulateLatency: true})
+	require.NoError(t, err)
+	server := httptest.NewServer(nearest)
+	defer server.Close()
+
+	start := time.Now()
+	status, body, _ := get(t, server, "GET", "/api/users/2", "")
+	require.Equal(t, 200, status)
+	require.Equal(t, "user", body)
+	require.True(t, time.Since(start) >= 30*time.Millisecond)
+
+	strict, err := New(h, Options{Unmatched: UnmatchedError})
+	require.NoError(t, err)
+	strictServer := httptest.NewServer(strict)
+	defer strictServer.Close()
+
+	status, body, _ = get(t, strictServer, "GET", "/api/users/2", "")
+	require.Equal(t, http.StatusBadGateway, status)
+	require.Contains(t, body, "passthrough is disabled")
+}
+
+func TestServerDefaultOptions(t *testing.T) {
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{
+		hartest.NewEntry("GET", "https://example.com/api/users/1").Status(200).Body("secure").Build(),
+		hartest.NewEntry("GET", "http://example.com/api/users/1").Status(200).Body("plain").Build(),
+		hartest.NewEntry("GET", "https://other.example.com/api/orders/1").Status(200).Body("order").Build(),
+	}}}
+
+	replay, err := New(h, Options{})
+	require.NoError(t, err)
+	server := httptest.NewServer(replay)
+	defer server.Close()
+
+	status, body, _ := get(t, server, "GET", "/api/users/1", "")
+	require.Equal(t, 200, status)
+	require.Equal(t, "secure", body)
+
+	proxyURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	caCert, err := replay.CACertificate()
+	require.NoError(t, err)
+	again, err := replay.CACertificate()
+	require.NoError(t, err)
+	require.True(t, caCert == again)
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	client := &http.Client{Transport: &http.Transport{
+		Proxy:           http.ProxyURL(proxyURL),
+		TLSClientConfig: &tls.Config{RootCAs: roots},
+	}}
+
+	proxied := func(rawURL string) (int, string) {
+		response, err := client.Get(rawURL)
+		require.NoError(t, err)
+		defer response.Body.Close()
+
+		data, err := ioutil.ReadAll(response.Body)
+		require.NoError(t, err)
+		return response.StatusCode, string(data)
+	}
+
+	status, body = proxied("http://example.com/api/users/1")
+	require.Equal(t, 200, status)
+	require.Equal(t, "plain", body)
+
+	status, body = proxied("https://example.com/api/users/1")
+	require.Equal(t, 200, status)
+	require.Equal(t, "secure", body)
+
+	status, _ = proxied("https://other.example.com/api/users/1")
+	require.Equal(t, http.StatusNotFound, status)
+
+	status, body = proxied("https://other.example.com:443/api/orders/1")
+	require.Equal(t, 200, status)
+	require.Equal(t, "order", body)
+}
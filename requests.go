@@ -11,6 +11,8 @@ const (
 	MethodNetworkResponseReceived  = "Network.responseReceived"
 	MethodNetworkDataReceived      = "Network.dataReceived"
 	MethodNetworkLoadingFinished   = "Network.loadingFinished"
+
+	MethodNetworkRequestServedFromCache = "Network.requestServedFromCache"
 )
 
 type requestParams struct {
@@ -19,35 +21,67 @@ type requestParams struct {
 	networkResponseReceived          NetworkResponseReceived
 	networkDatasReceived             []NetworkDataReceived
 	networkLoadingFinished           NetworkLoadingFinished
+	servedFromCache                  bool
 }
 
 func (rp *requestParams) completed() bool {
 	return rp.networkLoadingFinished.RequestID != ""
 }
 
-func paramsByRequest(chromeLogEntries []ChromeLogEntry) (map[string]*requestParams, error) {
+// paramsByRequest correlates events by request. Events whose
+// Network.requestWillBeSent never arrived are returned as orphans by request
+// ID.
+func paramsByRequest(events []Event) (map[string]*requestParams, map[string][]Event, error) {
 	paramsByRequest := make(map[string]*requestParams)
+	orphansByRequest := make(map[string][]Event)
 
-	for _, chromeLogEntry := range chromeLogEntries {
-		var err error
-
-		switch chromeLogEntry.Message.Method {
-		case MethodNetworkRequestWillBeSent:
-			err = processNetworkRequestWillBeSent(paramsByRequest, chromeLogEntry.Message.Params)
-		case MethodNetworkResponseReceived:
-			err = processNetworkResponseReceived(paramsByRequest, chromeLogEntry.Message.Params)
-		case MethodNetworkDataReceived:
-			err = processNetworkDataReceived(paramsByRequest, chromeLogEntry.Message.Params)
-		case MethodNetworkLoadingFinished:
-			err = processNetworkLoadingFinished(paramsByRequest, chromeLogEntry.Message.Params)
+	for _, event := range events {
+		if err := processRequestEvent(paramsByRequest, orphansByRequest, event); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse entry %q", event.Method)
 		}
+	}
 
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to parse entry %q", chromeLogEntry.Message.Method)
-		}
+	return paramsByRequest, orphansByRequest, nil
+}
+
+func processRequestEvent(paramsByRequest map[string]*requestParams, orphansByRequest map[string][]Event, event Event) error {
+	var err error
+
+	switch event.Method {
+	case MethodNetworkRequestWillBeSent:
+		err = processNetworkRequestWillBeSent(paramsByRequest, event.Params)
+	case MethodNetworkResponseReceived:
+		err = processNetworkResponseReceived(paramsByRequest, event.Params)
+	case MethodNetworkDataReceived:
+		err = processNetworkDataReceived(paramsByRequest, event.Params)
+	case MethodNetworkLoadingFinished:
+		err = processNetworkLoadingFinished(paramsByRequest, event.Params)
+	case MethodNetworkRequestServedFromCache:
+		err = processNetworkRequestServedFromCache(paramsByRequest, event.Params)
+	default:
+		return nil
 	}
 
-	return paramsByRequest, nil
+	if missing, ok := err.(missingRequestError); ok {
+		orphansByRequest[string(missing)] = append(orphansByRequest[string(missing)], event)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if event.Method != MethodNetworkRequestWillBeSent {
+		return nil
+	}
+
+	requestID := requestID(event.Params)
+	orphans := orphansByRequest[requestID]
+	delete(orphansByRequest, requestID)
+	for _, orphan := range orphans {
+		if err := processRequestEvent(paramsByRequest, orphansByRequest, orphan); err != nil {
+			return errors.Wrapf(err, "failed to parse buffered entry %q", orphan.Method)
+		}
+	}
+	return nil
 }
 
 func processNetworkRequestWillBeSent(paramsByRequest map[string]*requestParams, params json.RawMessage) error {
@@ -80,7 +114,7 @@ func processNetworkRequestWillBeSentRedirect(paramsByRequest map[string]*request
 
 	request, ok := paramsByRequest[data.RequestID]
 	if !ok {
-		return errors.Errorf("missing entry for request %q", data.RequestID)
+		return missingRequestError(data.RequestID)
 	}
 
 	request.networkRequestWillBeSentRedirect = &data
@@ -95,7 +129,7 @@ func processNetworkResponseReceived(paramsByRequest map[string]*requestParams, p
 
 	request, ok := paramsByRequest[data.RequestID]
 	if !ok {
-		return errors.Errorf("missing entry for request %q", data.RequestID)
+		return missingRequestError(data.RequestID)
 	}
 
 	request.networkResponseReceived = data
@@ -110,7 +144,7 @@ func processNetworkDataReceived(paramsByRequest map[string]*requestParams, param
 
 	request, ok := paramsByRequest[data.RequestID]
 	if !ok {
-		return errors.Errorf("missing entry for request %q", data.RequestID)
+		return missingRequestError(data.RequestID)
 	}
 
 	request.networkDatasReceived = append(request.networkDatasReceived, data)
@@ -125,9 +159,24 @@ func processNetworkLoadingFinished(paramsByRequest map[string]*requestParams, pa
 
 	request, ok := paramsByRequest[data.RequestID]
 	if !ok {
-		return errors.Errorf("missing entry for request %q", data.RequestID)
+		return missingRequestError(data.RequestID)
 	}
 
 	request.networkLoadingFinished = data
 	return nil
 }
+
+func processNetworkRequestServedFromCache(paramsByRequest map[string]*requestParams, params json.RawMessage) error {
+	var data NetworkRequestServedFromCache
+	if err := json.Unmarshal(params, &data); err != nil {
+		return errors.Wrap(err, "failed to unmarshal NetworkRequestServedFromCache data")
+	}
+
+	request, ok := paramsByRequest[data.RequestID]
+	if !ok {
+		return missingRequestError(data.RequestID)
+	}
+
+	request.servedFromCache = true
+	return nil
+}
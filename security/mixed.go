@@ -0,0 +1,83 @@
+package security
+
+import (
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+)
+
+type MixedContentKind = chromedriver2har.MixedContentKind
+
+const (
+	MixedContentBlockable           = chromedriver2har.MixedContentBlockable
+	MixedContentOptionallyBlockable = chromedriver2har.MixedContentOptionallyBlockable
+	InsecureFormPost                = chromedriver2har.InsecureFormPost
+	InsecureSubresource             = chromedriver2har.InsecureSubresource
+)
+
+type MixedContent struct {
+	Kind        MixedContentKind `json:"kind"`
+	Method      string           `json:"method"`
+	URL         string           `json:"url"`
+	DocumentURL string           `json:"documentURL"`
+	PageRef     string           `json:"pageref,omitempty"`
+}
+
+// FindMixedContent lists the entries annotated as mixed content when the HAR
+// was built, and classifies entries of HARs built without the annotation.
+func FindMixedContent(h *har.HAR) []MixedContent {
+	pageTitles := make(map[string]string, len(h.Log.Pages))
+	for _, page := range h.Log.Pages {
+		pageTitles[page.ID] = page.Title
+	}
+
+	findings := make([]MixedContent, 0)
+	for _, entry := range h.Log.Entries {
+		pageRef := ""
+		if entry.PageRef != nil {
+			pageRef = *entry.PageRef
+		}
+
+		documentURL := entry.Custom.String(chromedriver2har.CustomDocumentURL)
+		if documentURL == "" {
+			documentURL = pageTitles[pageRef]
+		}
+
+		kind, ok := MixedContentKind(entry.Custom.String(chromedriver2har.CustomMixedContent)), true
+		if kind == "" {
+			kind, ok = chromedriver2har.ClassifyMixedContent(entry, documentURL)
+		}
+		if !ok {
+			continue
+		}
+
+		findings = append(findings, MixedContent{
+			Kind:        kind,
+			Method:      entry.Request.Method,
+			URL:         entry.Request.URL.String(),
+			DocumentURL: documentURL,
+			PageRef:     pageRef,
+		})
+	}
+	return findings
+}
+
+// NewMixedContent returns the findings that aren't in baseline, such as
+// the report of a previous run, matching them on kind, method and URL.
+func NewMixedContent(findings, baseline []MixedContent) []MixedContent {
+	known := make(map[MixedContent]bool, len(baseline))
+	for _, finding := range baseline {
+		known[mixedContentKey(finding)] = true
+	}
+
+	newFindings := make([]MixedContent, 0)
+	for _, finding := range findings {
+		if !known[mixedContentKey(finding)] {
+			newFindings = append(newFindings, finding)
+		}
+	}
+	return newFindings
+}
+
+func mixedContentKey(finding MixedContent) MixedContent {
+	return MixedContent{Kind: finding.Kind, Method: finding.Method, URL: finding.URL}
+}
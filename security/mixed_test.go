@@ -0,0 +1,50 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/internal/hartest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindMixedContent(t *testing.T) {
+	documentURL, mixedContentType := chromedriver2har.CustomDocumentURL, chromedriver2har.CustomMixedContentType
+
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{
+		hartest.NewEntry("GET", "https://example.com/").Custom(chromedriver2har.CustomResourceType, "Document").Custom(documentURL, "https://example.com/").Build(),
+		hartest.NewEntry("GET", "http://cdn.example.com/app.js").Custom(documentURL, "https://example.com/").Custom(mixedContentType, "blockable").Build(),
+		hartest.NewEntry("GET", "http://cdn.example.com/logo.png").Custom(documentURL, "https://example.com/").Custom(mixedContentType, "optionally-blockable").Build(),
+		hartest.NewEntry("GET", "http://tracker.example.com/pixel").Custom(documentURL, "https://example.com/").Custom(mixedContentType, "none").Build(),
+		hartest.NewEntry("GET", "http://plain.example.com/style.css").Custom(documentURL, "http://plain.example.com/").Build(),
+		hartest.NewEntry("POST", "http://login.example.com/submit").Custom(chromedriver2har.CustomResourceType, "Document").RequestHeader("Origin", "https://example.com").Build(),
+	}}}
+
+	kinds := make([]MixedContentKind, 0)
+	for _, finding := range FindMixedContent(h) {
+		kinds = append(kinds, finding.Kind)
+	}
+	require.Equal(t, []MixedContentKind{MixedContentBlockable, MixedContentOptionallyBlockable, InsecureSubresource, InsecureFormPost}, kinds)
+}
+
+func TestFindMixedContentAnnotated(t *testing.T) {
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{
+		hartest.NewEntry("GET", "http://cdn.example.com/app.js").Custom(chromedriver2har.CustomMixedContent, "insecure-subresource").Build(),
+	}}}
+
+	findings := FindMixedContent(h)
+	require.Len(t, findings, 1)
+	require.Equal(t, InsecureSubresource, findings[0].Kind)
+}
+
+func TestNewMixedContent(t *testing.T) {
+	baseline := []MixedContent{{Kind: InsecureSubresource, Method: "GET", URL: "http://cdn.example.com/app.js", DocumentURL: "https://example.com/"}}
+	findings := []MixedContent{
+		{Kind: InsecureSubresource, Method: "GET", URL: "http://cdn.example.com/app.js", DocumentURL: "https://example.com/other"},
+		{Kind: MixedContentBlockable, Method: "GET", URL: "http://cdn.example.com/app.js"},
+		{Kind: InsecureSubresource, Method: "GET", URL: "http://cdn.example.com/new.js"},
+	}
+
+	require.Equal(t, findings[1:], NewMixedContent(findings, baseline))
+}
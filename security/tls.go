@@ -0,0 +1,125 @@
+package security
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+)
+
+const defaultExpiryWindow = 30 * 24 * time.Hour
+
+var (
+	weakProtocols = map[string]bool{
+		"SSL 2.0": true,
+		"SSL 3.0": true,
+		"TLS 1.0": true,
+		"TLS 1.1": true,
+	}
+	weakCipherFragments = []string{"RC4", "3DES", "DES_", "NULL", "EXPORT", "MD5"}
+	weakKeyExchanges    = map[string]bool{
+		"RSA": true,
+	}
+)
+
+type TLSOptions struct {
+	ExpiryWindow time.Duration
+	Now          time.Time
+}
+
+type HostTLS struct {
+	Host    string                           `json:"host"`
+	Details chromedriver2har.SecurityDetails `json:"details"`
+	ValidTo time.Time                        `json:"validTo"`
+	Issues  []string                         `json:"issues"`
+}
+
+func SecurityDetails(entry har.Entry) (*chromedriver2har.SecurityDetails, error) {
+	var details chromedriver2har.SecurityDetails
+	ok, err := entry.Custom.Decode(chromedriver2har.CustomSecurityDetails, &details)
+	if !ok || err != nil {
+		return nil, err
+	}
+	return &details, nil
+}
+
+func TLSIssues(h *har.HAR, opts TLSOptions) ([]HostTLS, error) {
+	expiryWindow := opts.ExpiryWindow
+	if expiryWindow <= 0 {
+		expiryWindow = defaultExpiryWindow
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	seen := make(map[string]bool)
+	hosts := make([]HostTLS, 0)
+
+	for _, entry := range h.Log.Entries {
+		host := entry.Request.URL.Hostname()
+		if seen[host] {
+			continue
+		}
+
+		details, err := SecurityDetails(entry)
+		if err != nil {
+			return nil, err
+		} else if details == nil {
+			continue
+		}
+		seen[host] = true
+
+		hostTLS := HostTLS{
+			Host:    host,
+			Details: *details,
+			ValidTo: time.Unix(int64(details.ValidTo), 0),
+			Issues:  tlsIssues(*details, now, expiryWindow),
+		}
+		if len(hostTLS.Issues) > 0 {
+			hosts = append(hosts, hostTLS)
+		}
+	}
+
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].Host < hosts[j].Host
+	})
+	return hosts, nil
+}
+
+func tlsIssues(details chromedriver2har.SecurityDetails, now time.Time, expiryWindow time.Duration) []string {
+	issues := make([]string, 0)
+
+	if weakProtocols[details.Protocol] {
+		issues = append(issues, fmt.Sprintf("weak protocol %s", details.Protocol))
+	}
+
+	for _, fragment := range weakCipherFragments {
+		if strings.Contains(details.Cipher, fragment) {
+			issues = append(issues, fmt.Sprintf("weak cipher %s", details.Cipher))
+			break
+		}
+	}
+
+	if weakKeyExchanges[details.KeyExchange] {
+		issues = append(issues, fmt.Sprintf("key exchange %s lacks forward secrecy", details.KeyExchange))
+	}
+
+	if details.CertificateTransparencyCompliance == "not-compliant" {
+		issues = append(issues, "certificate is not certificate transparency compliant")
+	}
+
+	if details.ValidTo > 0 {
+		validTo := time.Unix(int64(details.ValidTo), 0)
+		if validTo.Before(now) {
+			issues = append(issues, fmt.Sprintf("certificate expired on %s", validTo.UTC().Format("2006-01-02")))
+		} else if validTo.Before(now.Add(expiryWindow)) {
+			issues = append(issues, fmt.Sprintf("certificate expires on %s", validTo.UTC().Format("2006-01-02")))
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,37 @@
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/internal/hartest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSIssues(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{
+		hartest.NewEntry("GET", "https://good.example.com/").Custom(chromedriver2har.CustomSecurityDetails, chromedriver2har.SecurityDetails{
+			Protocol: "TLS 1.3",
+			Cipher:   "AES_128_GCM",
+			ValidTo:  float64(now.Add(365 * 24 * time.Hour).Unix()),
+		}).Build(),
+		hartest.NewEntry("GET", "https://old.example.com/").Custom(chromedriver2har.CustomSecurityDetails, json.RawMessage(fmt.Sprintf(`{"protocol":"TLS 1.0","keyExchange":"RSA","cipher":"3DES_EDE_CBC","validTo":%d}`, now.Add(7*24*time.Hour).Unix()))).Build(),
+	}}}
+
+	hosts, err := TLSIssues(h, TLSOptions{Now: now})
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+	require.Equal(t, "old.example.com", hosts[0].Host)
+	require.Equal(t, []string{
+		"weak protocol TLS 1.0",
+		"weak cipher 3DES_EDE_CBC",
+		"key exchange RSA lacks forward secrecy",
+		"certificate expires on 2026-10-24",
+	}, hosts[0].Issues)
+}
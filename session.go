@@ -0,0 +1,173 @@
+package chromedriver2har
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fedesog/webdriver"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/pkg/errors"
+)
+
+const (
+	performanceLogType   = "performance"
+	defaultDrainInterval = 5 * time.Second
+)
+
+type CaptureOptions struct {
+	TraceCategories []string
+	DrainInterval   time.Duration
+}
+
+// logReader reads a webdriver log, as *webdriver.Session does.
+type logReader interface {
+	Log(logType string) ([]webdriver.LogEntry, error)
+}
+
+type Capture struct {
+	session       logReader
+	drainInterval time.Duration
+
+	mu         sync.Mutex
+	logEntries []webdriver.LogEntry
+	drainErr   error
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	har      *har.HAR
+	stopErr  error
+}
+
+func PerformanceLoggingCapabilities(capabilities webdriver.Capabilities, opts CaptureOptions) webdriver.Capabilities {
+	merged := make(webdriver.Capabilities, len(capabilities)+2)
+	for key, value := range capabilities {
+		merged[key] = value
+	}
+
+	loggingPrefs := map[string]interface{}{performanceLogType: string(webdriver.LogAll)}
+	merged["goog:loggingPrefs"] = loggingPrefs
+	merged["loggingPrefs"] = loggingPrefs
+
+	perfLoggingPrefs := map[string]interface{}{
+		"enableNetwork": true,
+		"enablePage":    true,
+	}
+	if len(opts.TraceCategories) > 0 {
+		perfLoggingPrefs["traceCategories"] = strings.Join(opts.TraceCategories, ",")
+	}
+
+	chromeOptions := make(map[string]interface{})
+	if existing, ok := merged["goog:chromeOptions"].(map[string]interface{}); ok {
+		for key, value := range existing {
+			chromeOptions[key] = value
+		}
+	}
+	chromeOptions["perfLoggingPrefs"] = perfLoggingPrefs
+	merged["goog:chromeOptions"] = chromeOptions
+
+	return merged
+}
+
+func NewSession(driver *webdriver.ChromeDriver, desired, required webdriver.Capabilities, opts CaptureOptions) (*webdriver.Session, error) {
+	session, err := driver.NewSession(PerformanceLoggingCapabilities(desired, opts), required)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create session")
+	}
+	return session, nil
+}
+
+func StartCapture(session *webdriver.Session, opts CaptureOptions) (*Capture, error) {
+	return startCapture(session, opts)
+}
+
+func startCapture(session logReader, opts CaptureOptions) (*Capture, error) {
+	if _, err := session.Log(performanceLogType); err != nil {
+		return nil, errors.Wrap(err, "failed to clear performance log")
+	}
+
+	drainInterval := opts.DrainInterval
+	if drainInterval <= 0 {
+		drainInterval = defaultDrainInterval
+	}
+
+	c := &Capture{
+		session:       session,
+		drainInterval: drainInterval,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go c.run()
+	return c, nil
+}
+
+func (c *Capture) run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.drainInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.drain(); err != nil {
+				c.mu.Lock()
+				c.drainErr = err
+				c.mu.Unlock()
+				return
+			}
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *Capture) drain() error {
+	logEntries, err := c.session.Log(performanceLogType)
+	if err != nil {
+		return errors.Wrap(err, "failed to read performance log")
+	}
+
+	c.mu.Lock()
+	c.logEntries = append(c.logEntries, logEntries...)
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Capture) LogEntries() []webdriver.LogEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	logEntries := make([]webdriver.LogEntry, len(c.logEntries))
+	copy(logEntries, c.logEntries)
+	return logEntries
+}
+
+// Stop ends the capture and builds a HAR from the drained log entries. If
+// draining failed, the HAR covers the entries read before the failure and is
+// returned together with the error. Later calls return the same result.
+func (c *Capture) Stop() (*har.HAR, error) {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+		<-c.done
+		c.har, c.stopErr = c.finish()
+	})
+	return c.har, c.stopErr
+}
+
+func (c *Capture) finish() (*har.HAR, error) {
+	c.mu.Lock()
+	drainErr := c.drainErr
+	c.mu.Unlock()
+
+	if drainErr == nil {
+		drainErr = c.drain()
+	}
+
+	h, err := New(c.LogEntries())
+	if err != nil {
+		return nil, err
+	}
+	return h, drainErr
+}
@@ -0,0 +1,100 @@
+package chromedriver2har
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fedesog/webdriver"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerformanceLoggingCapabilities(t *testing.T) {
+	capabilities := webdriver.Capabilities{
+		"browserName":        "chrome",
+		"goog:chromeOptions": map[string]interface{}{"args": []string{"--headless"}},
+	}
+
+	merged := PerformanceLoggingCapabilities(capabilities, CaptureOptions{TraceCategories: []string{"devtools.timeline", "blink.user_timing"}})
+
+	require.Equal(t, "chrome", merged["browserName"])
+	require.Equal(t, map[string]interface{}{"performance": "ALL"}, merged["goog:loggingPrefs"])
+	require.Equal(t, map[string]interface{}{
+		"args": []string{"--headless"},
+		"perfLoggingPrefs": map[string]interface{}{
+			"enableNetwork":   true,
+			"enablePage":      true,
+			"traceCategories": "devtools.timeline,blink.user_timing",
+		},
+	}, merged["goog:chromeOptions"])
+	require.NotContains(t, capabilities["goog:chromeOptions"], "perfLoggingPrefs")
+}
+
+// gatedLogReader answers the first Log call, which clears the log, with
+// nothing and every later call with the next value sent on reads, so tests
+// decide when each drain happens. Once reads is closed, calls return err.
+type gatedLogReader struct {
+	mu      sync.Mutex
+	cleared bool
+	reads   chan []webdriver.LogEntry
+	err     error
+}
+
+func newGatedLogReader(err error) *gatedLogReader {
+	return &gatedLogReader{reads: make(chan []webdriver.LogEntry), err: err}
+}
+
+func (g *gatedLogReader) Log(logType string) ([]webdriver.LogEntry, error) {
+	g.mu.Lock()
+	cleared := g.cleared
+	g.cleared = true
+	g.mu.Unlock()
+
+	if !cleared {
+		return nil, nil
+	}
+	logEntries, ok := <-g.reads
+	if !ok {
+		return nil, g.err
+	}
+	return logEntries, nil
+}
+
+func TestCaptureLifecycle(t *testing.T) {
+	session := newGatedLogReader(nil)
+	c, err := startCapture(session, CaptureOptions{DrainInterval: time.Millisecond})
+	require.NoError(t, err)
+
+	// The second read only starts once the first drain has been stored.
+	session.reads <- logEntries[:40]
+	session.reads <- logEntries[40:]
+	close(session.reads)
+
+	h, err := c.Stop()
+	require.NoError(t, err)
+	require.Len(t, c.LogEntries(), len(logEntries))
+	require.Len(t, h.Log.Pages, 1)
+	require.Len(t, h.Log.Entries, 17)
+
+	again, err := c.Stop()
+	require.NoError(t, err)
+	require.True(t, h == again)
+}
+
+func TestCaptureStopAfterDrainError(t *testing.T) {
+	session := newGatedLogReader(errors.New("session deleted"))
+	c, err := startCapture(session, CaptureOptions{DrainInterval: time.Millisecond})
+	require.NoError(t, err)
+
+	session.reads <- logEntries
+	close(session.reads)
+
+	h, err := c.Stop()
+	require.EqualError(t, err, "failed to read performance log: session deleted")
+	require.NotNil(t, h)
+	require.Len(t, h.Log.Entries, 17)
+
+	_, err = c.Stop()
+	require.Error(t, err)
+}
@@ -0,0 +1,183 @@
+package chromedriver2har
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/fedesog/webdriver"
+	"github.com/pkg/errors"
+)
+
+type logEntrySource struct {
+	logEntries []webdriver.LogEntry
+}
+
+func NewLogEntrySource(logEntries []webdriver.LogEntry) EventSource {
+	return &logEntrySource{logEntries: logEntries}
+}
+
+func (s *logEntrySource) Next() (Event, error) {
+	if len(s.logEntries) == 0 {
+		return Event{}, io.EOF
+	}
+	logEntry := s.logEntries[0]
+	s.logEntries = s.logEntries[1:]
+
+	var chromeLogEntry ChromeLogEntry
+	if err := json.Unmarshal([]byte(logEntry.Message), &chromeLogEntry); err != nil {
+		return Event{}, errors.Wrapf(err, "failed to unmarshal log entry at timestamp %d", logEntry.TimeStamp)
+	}
+
+	return chromeLogEntry.event(millisecondsTime(float64(logEntry.TimeStamp))), nil
+}
+
+func (e ChromeLogEntry) event(timestamp time.Time) Event {
+	return Event{
+		Method:    e.Message.Method,
+		Params:    e.Message.Params,
+		Timestamp: timestamp,
+		TargetID:  e.Webview,
+	}
+}
+
+type jsonEvent struct {
+	Method    string          `json:"method"`
+	Params    json.RawMessage `json:"params"`
+	SessionID string          `json:"sessionId"`
+	TargetID  string          `json:"targetId"`
+	Message   json.RawMessage `json:"message"`
+	Webview   string          `json:"webview"`
+	Timestamp float64         `json:"timestamp"`
+
+	// Set by DevTools performance traces, which hold trace events rather than
+	// protocol events.
+	TraceEvents []json.RawMessage `json:"traceEvents"`
+	Ph          string            `json:"ph"`
+}
+
+type jsonSource struct {
+	reader  *bufio.Reader
+	decoder *json.Decoder
+	inArray bool
+	pending []Event
+}
+
+func NewJSONSource(r io.Reader) EventSource {
+	return &jsonSource{reader: bufio.NewReader(r)}
+}
+
+func (s *jsonSource) Next() (Event, error) {
+	if len(s.pending) > 0 {
+		event := s.pending[0]
+		s.pending = s.pending[1:]
+		return event, nil
+	}
+
+	if s.decoder == nil {
+		if err := s.start(); err != nil {
+			return Event{}, err
+		}
+	}
+
+	if s.inArray && !s.decoder.More() {
+		return Event{}, io.EOF
+	}
+
+	var data json.RawMessage
+	if err := s.decoder.Decode(&data); err == io.EOF {
+		return Event{}, io.EOF
+	} else if err != nil {
+		return Event{}, errors.Wrap(err, "failed to decode event")
+	}
+
+	var raw jsonEvent
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Event{}, errors.Wrap(err, "failed to decode event")
+	}
+
+	switch {
+	case len(raw.TraceEvents) > 0:
+		for _, traceEvent := range raw.TraceEvents {
+			s.pending = append(s.pending, Event{Method: MethodTracingDataCollected, Params: traceEvent})
+		}
+		return s.Next()
+	case raw.Method == "" && len(raw.Message) == 0 && raw.Ph != "":
+		return Event{Method: MethodTracingDataCollected, Params: data}, nil
+	}
+
+	return raw.event()
+}
+
+func (s *jsonSource) start() error {
+	for {
+		b, err := s.reader.Peek(1)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Wrap(err, "failed to read events")
+		}
+
+		if !isJSONSpace(b[0]) {
+			s.inArray = b[0] == '['
+			break
+		}
+		s.reader.ReadByte()
+	}
+
+	s.decoder = json.NewDecoder(s.reader)
+	if s.inArray {
+		if _, err := s.decoder.Token(); err != nil {
+			return errors.Wrap(err, "failed to read array start")
+		}
+	}
+	return nil
+}
+
+func (raw jsonEvent) event() (Event, error) {
+	timestamp := time.Time{}
+	if raw.Timestamp > 0 {
+		timestamp = millisecondsTime(raw.Timestamp)
+	}
+
+	if len(raw.Message) == 0 {
+		return Event{
+			Method:    raw.Method,
+			Params:    raw.Params,
+			Timestamp: timestamp,
+			TargetID:  raw.TargetID,
+			SessionID: raw.SessionID,
+		}, nil
+	}
+
+	message := bytes.TrimSpace(raw.Message)
+	if len(message) > 0 && message[0] == '"' {
+		var inner string
+		if err := json.Unmarshal(message, &inner); err != nil {
+			return Event{}, errors.Wrap(err, "failed to unmarshal log entry message")
+		}
+		message = []byte(inner)
+
+		var chromeLogEntry ChromeLogEntry
+		if err := json.Unmarshal(message, &chromeLogEntry); err != nil {
+			return Event{}, errors.Wrap(err, "failed to unmarshal log entry")
+		}
+		return chromeLogEntry.event(timestamp), nil
+	}
+
+	var msg Message
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return Event{}, errors.Wrap(err, "failed to unmarshal message")
+	}
+	return ChromeLogEntry{Message: msg, Webview: raw.Webview}.event(timestamp), nil
+}
+
+func isJSONSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+func millisecondsTime(milliseconds float64) time.Time {
+	return time.Unix(0, int64(milliseconds*float64(time.Millisecond)))
+}
@@ -0,0 +1,32 @@
+package chromedriver2har
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSource(t *testing.T) {
+	tests := map[string]string{
+		"cdp array": `[
+			{"method":"Page.loadEventFired","params":{"timestamp":1.5},"sessionId":"S1"}
+		]`,
+		"chromedriver jsonl": `{"message":{"method":"Page.loadEventFired","params":{"timestamp":1.5}},"webview":"W1"}
+`,
+		"selenium log dump": `[{"level":"INFO","message":"{\"message\":{\"method\":\"Page.loadEventFired\",\"params\":{\"timestamp\":1.5}},\"webview\":\"W1\"}","timestamp":1760695200123}]`,
+	}
+
+	for name, input := range tests {
+		events, err := readEvents(NewJSONSource(strings.NewReader(input)))
+		require.NoError(t, err, name)
+		require.Len(t, events, 1, name)
+		require.Equal(t, MethodPageLoadEventFired, events[0].Method, name)
+		require.JSONEq(t, `{"timestamp":1.5}`, string(events[0].Params), name)
+	}
+
+	events, err := readEvents(NewJSONSource(strings.NewReader(tests["selenium log dump"])))
+	require.NoError(t, err)
+	require.Equal(t, "W1", events[0].TargetID)
+	require.Equal(t, int64(1760695200123), events[0].Timestamp.UnixNano()/1e6)
+}
@@ -0,0 +1,169 @@
+package stats
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+)
+
+const (
+	defaultSlowest = 10
+	unknown        = "unknown"
+)
+
+var cachedFetchOrigins = map[string]bool{
+	chromedriver2har.FetchOriginCache:         true,
+	chromedriver2har.FetchOriginPreloadCache:  true,
+	chromedriver2har.FetchOriginPrefetchCache: true,
+}
+
+type Options struct {
+	Slowest int
+}
+
+type Group struct {
+	Name     string `json:"name"`
+	Requests int    `json:"requests"`
+	Bytes    int64  `json:"bytes"`
+}
+
+type Request struct {
+	Method string  `json:"method"`
+	URL    string  `json:"url"`
+	Status int     `json:"status"`
+	Time   float64 `json:"time"`
+}
+
+type Stats struct {
+	Requests            int       `json:"requests"`
+	Bytes               int64     `json:"bytes"`
+	ByResourceType      []Group   `json:"byResourceType"`
+	ByDomain            []Group   `json:"byDomain"`
+	ByStatus            []Group   `json:"byStatus"`
+	Slowest             []Request `json:"slowest"`
+	CacheHitRatio       float64   `json:"cacheHitRatio"`
+	CompressionRatio    float64   `json:"compressionRatio"`
+	ConnectionReuseRate float64   `json:"connectionReuseRate"`
+}
+
+// TransferSize returns the response bytes transferred for an entry, falling
+// back to the decoded content size when the transfer size is unknown.
+func TransferSize(entry har.Entry) int64 {
+	if entry.Response.BodySize >= 0 {
+		return int64(entry.Response.BodySize)
+	}
+	if entry.Response.Content.Size > 0 {
+		return int64(entry.Response.Content.Size)
+	}
+	return 0
+}
+
+func Compute(h *har.HAR, opts Options) Stats {
+	if opts.Slowest <= 0 {
+		opts.Slowest = defaultSlowest
+	}
+
+	byResourceType := make(map[string]*Group)
+	byDomain := make(map[string]*Group)
+	byStatus := make(map[string]*Group)
+
+	s := Stats{}
+	var cacheHits int
+	var contentSize, compressed int64
+	var connections, reused int
+	seenConnections := make(map[string]bool)
+
+	for _, entry := range har.SortedByStart(h.Log.Entries) {
+		size := TransferSize(entry)
+		s.Requests++
+		s.Bytes += size
+
+		addToGroup(byResourceType, entry.Custom.String(chromedriver2har.CustomResourceType), size)
+		addToGroup(byDomain, entry.Request.URL.Hostname(), size)
+		addToGroup(byStatus, strconv.Itoa(entry.Response.Status), size)
+
+		if cachedFetchOrigins[entry.Custom.String(chromedriver2har.CustomFetchOrigin)] {
+			cacheHits++
+		}
+
+		content := entry.Response.Content
+		if content.Compression != nil && content.Size > 0 {
+			contentSize += int64(content.Size)
+			compressed += int64(*content.Compression)
+		}
+
+		if entry.Connection != nil && *entry.Connection != "" {
+			connections++
+			if seenConnections[*entry.Connection] {
+				reused++
+			}
+			seenConnections[*entry.Connection] = true
+		}
+	}
+
+	s.ByResourceType = sortedGroups(byResourceType)
+	s.ByDomain = sortedGroups(byDomain)
+	s.ByStatus = sortedGroups(byStatus)
+	s.Slowest = slowest(h.Log.Entries, opts.Slowest)
+	s.CacheHitRatio = ratio(int64(cacheHits), int64(s.Requests))
+	s.CompressionRatio = ratio(compressed, contentSize)
+	s.ConnectionReuseRate = ratio(int64(reused), int64(connections))
+	return s
+}
+
+func addToGroup(groups map[string]*Group, name string, size int64) {
+	if name == "" {
+		name = unknown
+	}
+
+	group, ok := groups[name]
+	if !ok {
+		group = &Group{Name: name}
+		groups[name] = group
+	}
+	group.Requests++
+	group.Bytes += size
+}
+
+func sortedGroups(groups map[string]*Group) []Group {
+	sorted := make([]Group, 0, len(groups))
+	for _, group := range groups {
+		sorted = append(sorted, *group)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Bytes != sorted[j].Bytes {
+			return sorted[i].Bytes > sorted[j].Bytes
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func slowest(entries []har.Entry, n int) []Request {
+	requests := make([]Request, 0, len(entries))
+	for _, entry := range entries {
+		requests = append(requests, Request{
+			Method: entry.Request.Method,
+			URL:    entry.Request.URL.String(),
+			Status: entry.Response.Status,
+			Time:   entry.Time,
+		})
+	}
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].Time > requests[j].Time
+	})
+
+	if len(requests) > n {
+		requests = requests[:n]
+	}
+	return requests
+}
+
+func ratio(numerator, denominator int64) float64 {
+	if denominator <= 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
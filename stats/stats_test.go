@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/internal/hartest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompute(t *testing.T) {
+	start := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	resourceType, fetchOrigin := chromedriver2har.CustomResourceType, chromedriver2har.CustomFetchOrigin
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{
+		hartest.NewEntry("GET", "https://example.com/app.js").Started(start.Add(3*time.Second)).Time(80).Connection("12").
+			Custom(resourceType, "Script").Custom(fetchOrigin, chromedriver2har.FetchOriginNetwork).
+			Status(200).BodySize(3000).ContentSize(9000).Compression(6000).Build(),
+		hartest.NewEntry("GET", "https://example.com/").Started(start).Time(250).Connection("12").
+			Custom(resourceType, "Document").Custom(fetchOrigin, chromedriver2har.FetchOriginNetwork).
+			Status(200).BodySize(1000).ContentSize(4000).Compression(3000).Build(),
+		hartest.NewEntry("GET", "https://cdn.example.net/logo.png").Started(start.Add(time.Second)).Time(5).Connection("").
+			Custom(resourceType, "Image").Custom(fetchOrigin, chromedriver2har.FetchOriginCache).
+			Status(200).BodySize(0).ContentSize(2000).Compression(0).Build(),
+		hartest.NewEntry("GET", "https://cdn.example.net/missing.png").Started(start.Add(2*time.Second)).Time(40).Connection("17").
+			Custom(resourceType, "Image").Custom(fetchOrigin, chromedriver2har.FetchOriginNetwork).
+			Status(404).BodySize(200).ContentSize(200).Compression(0).Build(),
+	}}}
+
+	s := Compute(h, Options{Slowest: 2})
+	require.Equal(t, 4, s.Requests)
+	require.Equal(t, int64(4200), s.Bytes)
+	require.Equal(t, []Group{
+		{Name: "Script", Requests: 1, Bytes: 3000},
+		{Name: "Document", Requests: 1, Bytes: 1000},
+		{Name: "Image", Requests: 2, Bytes: 200},
+	}, s.ByResourceType)
+	require.Equal(t, []Group{
+		{Name: "example.com", Requests: 2, Bytes: 4000},
+		{Name: "cdn.example.net", Requests: 2, Bytes: 200},
+	}, s.ByDomain)
+	require.Equal(t, []Group{
+		{Name: "200", Requests: 3, Bytes: 4000},
+		{Name: "404", Requests: 1, Bytes: 200},
+	}, s.ByStatus)
+	require.Equal(t, []Request{
+		{Method: "GET", URL: "https://example.com/", Status: 200, Time: 250},
+		{Method: "GET", URL: "https://example.com/app.js", Status: 200, Time: 80},
+	}, s.Slowest)
+	require.Equal(t, 0.25, s.CacheHitRatio)
+	require.Equal(t, 9000.0/15200.0, s.CompressionRatio)
+	require.Equal(t, 1.0/3.0, s.ConnectionReuseRate)
+}
@@ -0,0 +1,40 @@
+package chromedriver2har
+
+type targetEvents struct {
+	targetID string
+	events   []Event
+}
+
+func (e Event) target() string {
+	if e.TargetID != "" {
+		return e.TargetID
+	}
+	return e.SessionID
+}
+
+func eventsByTarget(events []Event) []targetEvents {
+	indexByTarget := make(map[string]int)
+	targets := make([]targetEvents, 0)
+
+	for _, event := range events {
+		targetID := event.target()
+		index, ok := indexByTarget[targetID]
+		if !ok {
+			index = len(targets)
+			indexByTarget[targetID] = index
+			targets = append(targets, targetEvents{targetID: targetID})
+		}
+		targets[index].events = append(targets[index].events, event)
+	}
+
+	return targets
+}
+
+func (t targetEvents) hasRequests() bool {
+	for _, event := range t.events {
+		if event.Method == MethodNetworkRequestWillBeSent {
+			return true
+		}
+	}
+	return false
+}
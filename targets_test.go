@@ -0,0 +1,42 @@
+package chromedriver2har
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func requestEvents(targetID, requestID, url string, timestamp float64) []Event {
+	event := func(method, params string) Event {
+		return Event{Method: method, Params: json.RawMessage(params), TargetID: targetID}
+	}
+
+	return []Event{
+		event(MethodNetworkRequestWillBeSent, fmt.Sprintf(`{"requestId":%q,"documentURL":%q,"request":{"url":%q,"method":"GET","headers":{}},"timestamp":%v,"wallTime":1760695200}`, requestID, url, url, timestamp)),
+		event(MethodNetworkResponseReceived, fmt.Sprintf(`{"requestId":%q,"timestamp":%v,"response":{"url":%q,"status":200,"headers":{},"mimeType":"text/html"}}`, requestID, timestamp+0.01, url)),
+		event(MethodNetworkLoadingFinished, fmt.Sprintf(`{"requestId":%q,"timestamp":%v,"encodedDataLength":100}`, requestID, timestamp+0.02)),
+	}
+}
+
+func TestFromEventSourceTargets(t *testing.T) {
+	events := append(requestEvents("main", "1.1", "https://example.com/", 10), requestEvents("popup", "1.1", "https://auth.example.com/", 11)...)
+
+	h, err := FromEventSource(NewSliceSource(events))
+	require.NoError(t, err)
+	require.Len(t, h.Log.Pages, 2)
+	require.Len(t, h.Log.Entries, 2)
+	for _, entry := range h.Log.Entries {
+		if entry.Request.URL.Host == "auth.example.com" {
+			require.Equal(t, "page_2", *entry.PageRef)
+		} else {
+			require.Equal(t, "page_1", *entry.PageRef)
+		}
+	}
+
+	harsByTarget, err := FromEventSourceByTarget(NewSliceSource(events))
+	require.NoError(t, err)
+	require.Len(t, harsByTarget, 2)
+	require.Equal(t, "https://auth.example.com/", harsByTarget["popup"].Log.Pages[0].Title)
+}
@@ -0,0 +1,489 @@
+package chromedriver2har
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	MethodTracingDataCollected = "Tracing.dataCollected"
+
+	// methodTracePaint carries paint markers from trace events to pages.
+	methodTracePaint = "Trace.paint"
+)
+
+const (
+	traceResourceSendRequest      = "ResourceSendRequest"
+	traceResourceReceiveResponse  = "ResourceReceiveResponse"
+	traceResourceReceivedData     = "ResourceReceivedData"
+	traceResourceFinish           = "ResourceFinish"
+	traceTracingStartedInBrowser  = "TracingStartedInBrowser"
+	traceFrameCommittedInBrowser  = "FrameCommittedInBrowser"
+	traceNavigationStart          = "navigationStart"
+	traceMarkDOMContent           = "MarkDOMContent"
+	traceDOMContentLoadedEventEnd = "domContentLoadedEventEnd"
+	traceMarkLoad                 = "MarkLoad"
+	traceLoadEventEnd             = "loadEventEnd"
+	traceFirstPaint               = "firstPaint"
+	traceFirstContentfulPaint     = "firstContentfulPaint"
+	traceFirstMeaningfulPaint     = "firstMeaningfulPaint"
+	traceLargestContentfulPaint   = "largestContentfulPaint::Candidate"
+)
+
+var tracePaintMarkers = map[string]string{
+	traceFirstPaint:             CustomFirstPaint,
+	traceFirstContentfulPaint:   CustomFirstContentfulPaint,
+	traceFirstMeaningfulPaint:   CustomFirstMeaningfulPaint,
+	traceLargestContentfulPaint: CustomLargestContentfulPaint,
+}
+
+type traceEvent struct {
+	event  TraceEvent
+	target string
+	wall   float64
+}
+
+type traceRequest struct {
+	target       string
+	url          string
+	resourceType string
+	status       int
+	dataReceived []NetworkDataReceived
+}
+
+type traceConverter struct {
+	frameParents    map[string]string
+	documentURLs    map[string]string
+	requests        map[string]*traceRequest
+	domContentFired map[string]bool
+	loadFired       map[string]bool
+	wallOffset      float64
+	convertNetwork  bool
+	events          []Event
+}
+
+// expandTraceEvents replaces Tracing.dataCollected events with the Network and
+// Page events they describe, so traces build HARs like any other capture.
+// Network events recorded alongside a trace take precedence over its resource
+// events.
+func expandTraceEvents(events []Event) ([]Event, error) {
+	others := make([]Event, 0, len(events))
+	traceEvents := make([]traceEvent, 0)
+	for _, event := range events {
+		if event.Method != MethodTracingDataCollected {
+			others = append(others, event)
+			continue
+		}
+
+		parsed, err := parseTraceEvents(event)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse trace events")
+		}
+		traceEvents = append(traceEvents, parsed...)
+	}
+
+	if len(traceEvents) == 0 {
+		return events, nil
+	}
+
+	sort.SliceStable(traceEvents, func(i, j int) bool {
+		return traceEvents[i].event.Ts < traceEvents[j].event.Ts
+	})
+
+	c := newTraceConverter(others, traceEvents)
+	for _, e := range traceEvents {
+		if err := c.process(e); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert trace event %q", e.event.Name)
+		}
+	}
+
+	return append(others, c.events...), nil
+}
+
+func parseTraceEvents(event Event) ([]traceEvent, error) {
+	var collected TracingDataCollected
+	if err := json.Unmarshal(event.Params, &collected); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal TracingDataCollected data")
+	}
+
+	// The DevTools protocol batches trace events, while chromedriver logs each
+	// one as its own entry.
+	rawEvents := collected.Value
+	if rawEvents == nil {
+		rawEvents = []json.RawMessage{event.Params}
+	}
+
+	wall := 0.0
+	if !event.Timestamp.IsZero() {
+		wall = float64(event.Timestamp.UnixNano()) / 1e9
+	}
+
+	traceEvents := make([]traceEvent, 0, len(rawEvents))
+	for _, raw := range rawEvents {
+		var data TraceEvent
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal TraceEvent data")
+		}
+		traceEvents = append(traceEvents, traceEvent{event: data, target: event.target(), wall: wall})
+	}
+	return traceEvents, nil
+}
+
+func newTraceConverter(others []Event, traceEvents []traceEvent) *traceConverter {
+	c := &traceConverter{
+		frameParents:    make(map[string]string),
+		documentURLs:    make(map[string]string),
+		requests:        make(map[string]*traceRequest),
+		domContentFired: make(map[string]bool),
+		loadFired:       make(map[string]bool),
+		convertNetwork:  true,
+	}
+
+	hasWallOffset := false
+	for _, event := range others {
+		switch event.Method {
+		case MethodNetworkRequestWillBeSent:
+			var data NetworkRequestWillBeSent
+			if err := json.Unmarshal(event.Params, &data); err == nil && data.WallTime > 0 && !hasWallOffset {
+				c.wallOffset = data.WallTime - data.Timestamp
+				hasWallOffset = true
+			}
+			c.convertNetwork = false
+		case MethodPageDOMContentEventFired:
+			c.domContentFired[event.target()] = true
+		case MethodPageLoadEventFired:
+			c.loadFired[event.target()] = true
+		}
+	}
+
+	if !hasWallOffset {
+		c.wallOffset, hasWallOffset = responseWallOffset(traceEvents)
+	}
+	if !hasWallOffset && traceEvents[0].wall > 0 {
+		c.wallOffset = traceEvents[0].wall - traceSeconds(traceEvents[0].event.Ts)
+	}
+
+	return c
+}
+
+// responseWallOffset relates the trace clock to wall time through the
+// response time Chrome records alongside received responses.
+func responseWallOffset(traceEvents []traceEvent) (float64, bool) {
+	for _, e := range traceEvents {
+		if e.event.Name != traceResourceReceiveResponse {
+			continue
+		}
+
+		var data TraceResourceReceiveResponse
+		if err := json.Unmarshal(e.event.Args.Data, &data); err == nil && data.ResponseTime > 0 {
+			return data.ResponseTime/1000 - traceSeconds(e.event.Ts), true
+		}
+	}
+	return 0, false
+}
+
+func (c *traceConverter) process(e traceEvent) error {
+	switch e.event.Name {
+	case traceResourceSendRequest:
+		return c.resourceSendRequest(e)
+	case traceResourceReceiveResponse:
+		return c.resourceReceiveResponse(e)
+	case traceResourceReceivedData:
+		return c.resourceReceivedData(e)
+	case traceResourceFinish:
+		return c.resourceFinish(e)
+	case traceTracingStartedInBrowser, traceFrameCommittedInBrowser, traceNavigationStart:
+		return c.frame(e)
+	case traceMarkDOMContent, traceDOMContentLoadedEventEnd:
+		return c.pageEvent(e, MethodPageDOMContentEventFired, c.domContentFired)
+	case traceMarkLoad, traceLoadEventEnd:
+		return c.pageEvent(e, MethodPageLoadEventFired, c.loadFired)
+	}
+
+	if _, ok := tracePaintMarkers[e.event.Name]; ok {
+		return c.paint(e)
+	}
+	return nil
+}
+
+func (c *traceConverter) resourceSendRequest(e traceEvent) error {
+	if !c.convertNetwork {
+		return nil
+	}
+
+	var data TraceResourceSendRequest
+	if err := json.Unmarshal(e.event.Args.Data, &data); err != nil {
+		return errors.Wrap(err, "failed to unmarshal TraceResourceSendRequest data")
+	}
+
+	// Redirects reuse the request ID. Like the Network domain, each hop is
+	// sent with a redirect response for the URL it leaves, so the entry ends
+	// up with the redirect URL and the final response.
+	var redirectResponse *Response
+	request, redirected := c.requests[data.RequestID]
+	if redirected {
+		redirectResponse = &Response{URL: request.url, Status: request.status, Headers: map[string]string{}}
+		if c.documentURLs[request.target] == request.url {
+			c.documentURLs[request.target] = data.URL
+		}
+		request.url = data.URL
+		request.status = 0
+		request.dataReceived = nil
+	} else {
+		target := c.target(data.Frame, e)
+		if c.documentURLs[target] == "" {
+			c.documentURLs[target] = data.URL
+		}
+		request = &traceRequest{target: target, url: data.URL, resourceType: data.ResourceType}
+		c.requests[data.RequestID] = request
+	}
+
+	timestamp := traceSeconds(e.event.Ts)
+	return c.emit(request.target, MethodNetworkRequestWillBeSent, NetworkRequestWillBeSent{
+		RequestID:   data.RequestID,
+		DocumentURL: c.documentURLs[request.target],
+		Request: Request{
+			URL:             data.URL,
+			Method:          data.RequestMethod,
+			Headers:         map[string]string{},
+			InitialPriority: data.Priority,
+		},
+		Timestamp:        timestamp,
+		WallTime:         timestamp + c.wallOffset,
+		Initiator:        Initiator{Type: "other"},
+		RedirectResponse: redirectResponse,
+		Type:             request.resourceType,
+	})
+}
+
+func (c *traceConverter) resourceReceiveResponse(e traceEvent) error {
+	if !c.convertNetwork {
+		return nil
+	}
+
+	var data TraceResourceReceiveResponse
+	if err := json.Unmarshal(e.event.Args.Data, &data); err != nil {
+		return errors.Wrap(err, "failed to unmarshal TraceResourceReceiveResponse data")
+	}
+
+	request, ok := c.requests[data.RequestID]
+	if !ok {
+		return nil
+	}
+
+	request.status = data.StatusCode
+
+	headers := make(map[string]string, len(data.Headers))
+	for _, header := range data.Headers {
+		if value, ok := headers[header.Name]; ok {
+			headers[header.Name] = value + "\n" + header.Value
+		} else {
+			headers[header.Name] = header.Value
+		}
+	}
+
+	fromDiskCache := data.FromCache
+	fromServiceWorker := data.FromServiceWorker
+	return c.emit(request.target, MethodNetworkResponseReceived, NetworkResponseReceived{
+		RequestID: data.RequestID,
+		Timestamp: traceSeconds(e.event.Ts),
+		Type:      request.resourceType,
+		Response: Response{
+			URL:               request.url,
+			Status:            data.StatusCode,
+			Headers:           headers,
+			MimeType:          data.MimeType,
+			ConnectionReused:  data.ConnectionReused,
+			ConnectionID:      data.ConnectionID,
+			FromDiskCache:     &fromDiskCache,
+			FromServiceWorker: &fromServiceWorker,
+			EncodedDataLength: data.EncodedDataLength,
+			Timing:            data.Timing,
+			Protocol:          data.Protocol,
+		},
+	})
+}
+
+func (c *traceConverter) resourceReceivedData(e traceEvent) error {
+	if !c.convertNetwork {
+		return nil
+	}
+
+	var data TraceResourceReceivedData
+	if err := json.Unmarshal(e.event.Args.Data, &data); err != nil {
+		return errors.Wrap(err, "failed to unmarshal TraceResourceReceivedData data")
+	}
+
+	if request, ok := c.requests[data.RequestID]; ok {
+		request.dataReceived = append(request.dataReceived, NetworkDataReceived{
+			RequestID:         data.RequestID,
+			Timestamp:         traceSeconds(e.event.Ts),
+			DataLength:        data.EncodedDataLength,
+			EncodedDataLength: data.EncodedDataLength,
+		})
+	}
+	return nil
+}
+
+// resourceFinish emits the received data and completion of a request. Chunks
+// only record their encoded length, so when the decoded body length is known
+// it is reported as a single chunk instead.
+func (c *traceConverter) resourceFinish(e traceEvent) error {
+	if !c.convertNetwork {
+		return nil
+	}
+
+	var data TraceResourceFinish
+	if err := json.Unmarshal(e.event.Args.Data, &data); err != nil {
+		return errors.Wrap(err, "failed to unmarshal TraceResourceFinish data")
+	}
+
+	request, ok := c.requests[data.RequestID]
+	if !ok || data.DidFail {
+		return nil
+	}
+
+	timestamp := data.FinishTime
+	if timestamp == 0 {
+		timestamp = traceSeconds(e.event.Ts)
+	}
+
+	encodedDataLength := 0
+	for _, dataReceived := range request.dataReceived {
+		encodedDataLength += dataReceived.EncodedDataLength
+	}
+
+	dataReceived := request.dataReceived
+	if data.DecodedBodyLength > 0 {
+		dataReceived = []NetworkDataReceived{{
+			RequestID:         data.RequestID,
+			Timestamp:         timestamp,
+			DataLength:        data.DecodedBodyLength,
+			EncodedDataLength: encodedDataLength,
+		}}
+	}
+	for _, d := range dataReceived {
+		if err := c.emit(request.target, MethodNetworkDataReceived, d); err != nil {
+			return err
+		}
+	}
+
+	if data.EncodedDataLength > 0 {
+		encodedDataLength = data.EncodedDataLength
+	}
+	return c.emit(request.target, MethodNetworkLoadingFinished, NetworkLoadingFinished{
+		RequestID:         data.RequestID,
+		Timestamp:         timestamp,
+		EncodedDataLength: encodedDataLength,
+	})
+}
+
+func (c *traceConverter) frame(e traceEvent) error {
+	var data TraceFrameData
+	if err := json.Unmarshal(e.event.Args.Data, &data); err != nil {
+		return errors.Wrap(err, "failed to unmarshal TraceFrameData data")
+	}
+
+	frames := append(data.Frames, TraceFrame{Frame: data.Frame, Parent: data.Parent})
+	for _, frame := range frames {
+		if frame.Frame != "" && frame.Parent != "" {
+			c.frameParents[frame.Frame] = frame.Parent
+		}
+	}
+
+	if e.event.Name == traceNavigationStart && data.IsLoadingMainFrame && data.DocumentLoaderURL != "" {
+		target := c.target(e.event.Args.Frame, e)
+		if c.documentURLs[target] == "" {
+			c.documentURLs[target] = data.DocumentLoaderURL
+		}
+	}
+	return nil
+}
+
+func (c *traceConverter) pageEvent(e traceEvent, method string, fired map[string]bool) error {
+	frame, ok, err := c.mainFrame(e)
+	if err != nil || !ok {
+		return err
+	}
+
+	target := c.target(frame, e)
+	if fired[target] {
+		return nil
+	}
+	fired[target] = true
+
+	timestamp := traceSeconds(e.event.Ts)
+	if method == MethodPageDOMContentEventFired {
+		return c.emit(target, method, PageDOMContentEventFired{Timestamp: timestamp})
+	}
+	return c.emit(target, method, PageLoadEventFired{Timestamp: timestamp})
+}
+
+func (c *traceConverter) paint(e traceEvent) error {
+	frame, ok, err := c.mainFrame(e)
+	if err != nil || !ok {
+		return err
+	}
+
+	return c.emit(c.target(frame, e), methodTracePaint, TracePaint{
+		Name:      tracePaintMarkers[e.event.Name],
+		Timestamp: traceSeconds(e.event.Ts),
+	})
+}
+
+// mainFrame returns the frame of a page marker, and whether that frame is a
+// main frame rather than an iframe.
+func (c *traceConverter) mainFrame(e traceEvent) (string, bool, error) {
+	var data TraceFrameData
+	if len(e.event.Args.Data) > 0 {
+		if err := json.Unmarshal(e.event.Args.Data, &data); err != nil {
+			return "", false, errors.Wrap(err, "failed to unmarshal TraceFrameData data")
+		}
+	}
+
+	frame := e.event.Args.Frame
+	if frame == "" {
+		frame = data.Frame
+	}
+
+	if data.IsMainFrame != nil {
+		return frame, *data.IsMainFrame, nil
+	}
+	return frame, c.frameParents[frame] == "", nil
+}
+
+// target names the target of a frame after its main frame, which is also the
+// DevTools target ID of the page.
+func (c *traceConverter) target(frame string, e traceEvent) string {
+	if frame == "" {
+		if e.target != "" {
+			return e.target
+		}
+		return fmt.Sprintf("pid-%d", e.event.Pid)
+	}
+
+	seen := map[string]bool{frame: true}
+	for parent := c.frameParents[frame]; parent != "" && !seen[parent]; parent = c.frameParents[frame] {
+		seen[parent] = true
+		frame = parent
+	}
+	return frame
+}
+
+func (c *traceConverter) emit(target, method string, params interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %q params", method)
+	}
+	c.events = append(c.events, Event{Method: method, Params: data, TargetID: target})
+	return nil
+}
+
+// traceSeconds converts trace timestamps, in microseconds, to the seconds used
+// by DevTools protocol events on the same clock.
+func traceSeconds(ts float64) float64 {
+	return ts / 1e6
+}
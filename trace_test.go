@@ -0,0 +1,117 @@
+package chromedriver2har
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const devToolsTrace = `{"traceEvents":[
+{"name":"TracingStartedInBrowser","ph":"I","ts":999000000,"pid":1,"tid":1,"args":{"data":{"frames":[{"frame":"MAIN","url":"https://example.com/","processId":2},{"frame":"CHILD","parent":"MAIN","url":"https://ads.example.net/","processId":3}]}}},
+{"name":"navigationStart","ph":"R","ts":999900000,"pid":2,"tid":1,"args":{"frame":"MAIN","data":{"documentLoaderURL":"https://example.com/","isLoadingMainFrame":true}}},
+{"name":"ResourceSendRequest","ph":"I","ts":1000000000,"pid":2,"tid":1,"args":{"data":{"requestId":"R1","frame":"MAIN","url":"https://example.com/","requestMethod":"GET","priority":"VeryHigh","resourceType":"Document"}}},
+{"name":"ResourceReceiveResponse","ph":"I","ts":1000100000,"pid":2,"tid":1,"args":{"data":{"requestId":"R1","frame":"MAIN","statusCode":200,"mimeType":"text/html","encodedDataLength":150,"responseTime":1700000000100,"protocol":"h2","connectionId":7,"headers":[{"name":"content-type","value":"text/html"},{"name":"set-cookie","value":"a=1"},{"name":"set-cookie","value":"b=2"}],"timing":{"requestTime":1000,"dnsStart":-1,"dnsEnd":-1,"connectStart":-1,"connectEnd":-1,"sslStart":-1,"sslEnd":-1,"sendStart":1,"sendEnd":2,"receiveHeadersEnd":90}}}},
+{"name":"ResourceReceivedData","ph":"I","ts":1000150000,"pid":2,"tid":1,"args":{"data":{"requestId":"R1","frame":"MAIN","encodedDataLength":400}}},
+{"name":"ResourceReceivedData","ph":"I","ts":1000160000,"pid":2,"tid":1,"args":{"data":{"requestId":"R1","frame":"MAIN","encodedDataLength":600}}},
+{"name":"ResourceFinish","ph":"I","ts":1000200000,"pid":2,"tid":1,"args":{"data":{"requestId":"R1","didFail":false,"encodedDataLength":1150,"decodedBodyLength":4000,"finishTime":1000.2}}},
+{"name":"ResourceSendRequest","ph":"I","ts":1000300000,"pid":3,"tid":1,"args":{"data":{"requestId":"R2","frame":"CHILD","url":"https://ads.example.net/ad.js","requestMethod":"GET","priority":"Low"}}},
+{"name":"ResourceReceiveResponse","ph":"I","ts":1000350000,"pid":3,"tid":1,"args":{"data":{"requestId":"R2","frame":"CHILD","statusCode":200,"mimeType":"text/javascript"}}},
+{"name":"ResourceReceivedData","ph":"I","ts":1000360000,"pid":3,"tid":1,"args":{"data":{"requestId":"R2","frame":"CHILD","encodedDataLength":50}}},
+{"name":"ResourceFinish","ph":"I","ts":1000400000,"pid":3,"tid":1,"args":{"data":{"requestId":"R2","didFail":false}}},
+{"name":"ResourceSendRequest","ph":"I","ts":1000410000,"pid":2,"tid":1,"args":{"data":{"requestId":"R3","frame":"MAIN","url":"https://example.com/missing.png","requestMethod":"GET"}}},
+{"name":"ResourceFinish","ph":"I","ts":1000420000,"pid":2,"tid":1,"args":{"data":{"requestId":"R3","didFail":true}}},
+{"name":"firstPaint","ph":"R","ts":1000500000,"pid":2,"tid":1,"args":{"frame":"MAIN","data":{"navigationId":"N"}}},
+{"name":"firstContentfulPaint","ph":"R","ts":1000500000,"pid":2,"tid":1,"args":{"frame":"MAIN","data":{"navigationId":"N"}}},
+{"name":"firstPaint","ph":"R","ts":1000550000,"pid":3,"tid":1,"args":{"frame":"CHILD","data":{"navigationId":"M"}}},
+{"name":"largestContentfulPaint::Candidate","ph":"R","ts":1000600000,"pid":2,"tid":1,"args":{"frame":"MAIN","data":{"isMainFrame":true,"size":100}}},
+{"name":"largestContentfulPaint::Candidate","ph":"R","ts":1000700000,"pid":2,"tid":1,"args":{"frame":"MAIN","data":{"isMainFrame":true,"size":900}}},
+{"name":"MarkDOMContent","ph":"I","ts":1000800000,"pid":2,"tid":1,"args":{"data":{"frame":"MAIN","isMainFrame":true}}},
+{"name":"domContentLoadedEventEnd","ph":"R","ts":1000810000,"pid":2,"tid":1,"args":{"frame":"MAIN"}},
+{"name":"MarkLoad","ph":"I","ts":1000900000,"pid":3,"tid":1,"args":{"data":{"frame":"CHILD","isMainFrame":false}}},
+{"name":"MarkLoad","ph":"I","ts":1001000000,"pid":2,"tid":1,"args":{"data":{"frame":"MAIN","isMainFrame":true}}}
+],"metadata":{"source":"DevTools"}}`
+
+func TestFromEventSourceDevToolsTrace(t *testing.T) {
+	h, err := FromEventSource(NewJSONSource(strings.NewReader(devToolsTrace)))
+	require.NoError(t, err)
+
+	require.Len(t, h.Log.Pages, 1)
+	page := h.Log.Pages[0]
+	require.Equal(t, "https://example.com/", page.Title)
+	require.Equal(t, time.Unix(1700000000, 0).UTC(), page.StartedDateTime.UTC().Round(time.Millisecond))
+	require.InDelta(t, 800, *page.PageTimings.OnContentLoad, 0.001)
+	require.InDelta(t, 1000, *page.PageTimings.OnLoad, 0.001)
+	require.InDelta(t, 500, page.Custom[CustomFirstPaint], 0.001)
+	require.InDelta(t, 500, page.Custom[CustomFirstContentfulPaint], 0.001)
+	require.InDelta(t, 700, page.Custom[CustomLargestContentfulPaint], 0.001)
+	require.NotContains(t, page.Custom, CustomFirstMeaningfulPaint)
+
+	require.Len(t, h.Log.Entries, 2)
+	document, ad := h.Log.Entries[0], h.Log.Entries[1]
+	require.Equal(t, "page_1", *document.PageRef)
+	require.Equal(t, "page_1", *ad.PageRef)
+
+	require.Equal(t, "https://example.com/", document.Request.URL.String())
+	require.Equal(t, "HTTP/2", document.Request.HTTPVersion)
+	require.Equal(t, 200, document.Response.Status)
+	require.Equal(t, 4000, document.Response.Content.Size)
+	require.Equal(t, "Document", document.Custom[CustomResourceType])
+	require.Equal(t, "7", *document.Connection)
+	require.InDelta(t, 200, document.Time, 0.001)
+	for _, header := range document.Response.Headers {
+		if header.Name == "set-cookie" {
+			require.Equal(t, "a=1\nb=2", header.Value)
+		}
+	}
+
+	require.Equal(t, "https://ads.example.net/ad.js", ad.Request.URL.String())
+	require.Equal(t, "https://example.com/", ad.Custom[CustomDocumentURL])
+	require.Equal(t, 50, ad.Response.Content.Size)
+
+	data, err := json.Marshal(page)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"_firstContentfulPaint":500`)
+}
+
+func TestFromEventSourceTraceRedirect(t *testing.T) {
+	trace := `{"traceEvents":[
+{"name":"ResourceSendRequest","ph":"I","ts":1000000000,"pid":2,"tid":1,"args":{"data":{"requestId":"R1","frame":"MAIN","url":"http://example.com/","requestMethod":"GET","resourceType":"Document"}}},
+{"name":"ResourceSendRequest","ph":"I","ts":1000050000,"pid":2,"tid":1,"args":{"data":{"requestId":"R1","frame":"MAIN","url":"https://example.com/","requestMethod":"GET","resourceType":"Document"}}},
+{"name":"ResourceReceiveResponse","ph":"I","ts":1000100000,"pid":2,"tid":1,"args":{"data":{"requestId":"R1","frame":"MAIN","statusCode":200,"mimeType":"text/html","responseTime":1700000000100}}},
+{"name":"ResourceFinish","ph":"I","ts":1000200000,"pid":2,"tid":1,"args":{"data":{"requestId":"R1","didFail":false,"encodedDataLength":100}}}
+]}`
+
+	h, err := FromEventSource(NewJSONSource(strings.NewReader(trace)))
+	require.NoError(t, err)
+
+	require.Len(t, h.Log.Entries, 1)
+	entry := h.Log.Entries[0]
+	require.Equal(t, "http://example.com/", entry.Request.URL.String())
+	require.Equal(t, "https://example.com/", entry.Response.RedirectURL.String())
+	require.Equal(t, 200, entry.Response.Status)
+}
+
+func TestFromEventSourceTraceWithNetworkEvents(t *testing.T) {
+	events := requestEvents("MAIN", "1.1", "https://example.com/", 1000)
+	traceEvent := func(params string) Event {
+		return Event{Method: MethodTracingDataCollected, Params: json.RawMessage(params), TargetID: "browser"}
+	}
+	events = append(events,
+		traceEvent(`{"name":"ResourceSendRequest","ph":"I","ts":1000000000,"args":{"data":{"requestId":"1.1","frame":"MAIN","url":"https://example.com/","requestMethod":"GET"}}}`),
+		traceEvent(`{"value":[{"name":"firstContentfulPaint","ph":"R","ts":1000250000,"args":{"frame":"MAIN"}},{"name":"firstMeaningfulPaint","ph":"R","ts":1000300000,"args":{"frame":"MAIN"}}]}`),
+		traceEvent(`{"name":"MarkLoad","ph":"I","ts":1000400000,"args":{"data":{"frame":"MAIN","isMainFrame":true}}}`),
+	)
+
+	h, err := FromEventSource(NewSliceSource(events))
+	require.NoError(t, err)
+	require.Len(t, h.Log.Pages, 1)
+	require.Len(t, h.Log.Entries, 1)
+
+	page := h.Log.Pages[0]
+	require.InDelta(t, 250, page.Custom[CustomFirstContentfulPaint], 0.001)
+	require.InDelta(t, 300, page.Custom[CustomFirstMeaningfulPaint], 0.001)
+	require.InDelta(t, 400, *page.PageTimings.OnLoad, 0.001)
+}
@@ -30,7 +30,9 @@ type NetworkRequestWillBeSent struct {
 	Request          Request   `json:"request"`
 	Timestamp        float64   `json:"timestamp"`
 	WallTime         float64   `json:"wallTime"`
+	Initiator        Initiator `json:"initiator"`
 	RedirectResponse *Response `json:"redirectResponse"`
+	Type             string    `json:"type"`
 }
 
 type NetworkResponseReceived struct {
@@ -48,12 +50,21 @@ type NetworkDataReceived struct {
 	EncodedDataLength int     `json:"encodedDataLength"`
 }
 
+type NetworkRequestServedFromCache struct {
+	RequestID string `json:"requestId"`
+}
+
 type NetworkLoadingFinished struct {
 	RequestID         string  `json:"requestId"`
 	Timestamp         float64 `json:"timestamp"`
 	EncodedDataLength int     `json:"encodedDataLength"`
 }
 
+type NetworkGetResponseBody struct {
+	Body          string `json:"body"`
+	Base64Encoded bool   `json:"base64Encoded"`
+}
+
 type Request struct {
 	URL              string            `json:"url"`
 	Method           string            `json:"method"`
@@ -61,28 +72,51 @@ type Request struct {
 	PostData         *string           `json:"postData"`
 	MixedContentType *string           `json:"mixedContentType"`
 	InitialPriority  string            `json:"initialPriority"`
+	IsLinkPreload    *bool             `json:"isLinkPreload"`
+}
+
+type Initiator struct {
+	Type       string   `json:"type"`
+	URL        *string  `json:"url"`
+	LineNumber *float64 `json:"lineNumber"`
 }
 
 type Response struct {
-	URL                string                 `json:"url"`
-	Status             int                    `json:"status"`
-	StatusText         string                 `json:"statusText"`
-	Headers            map[string]string      `json:"headers"`
-	HeadersText        *string                `json:"headersText"`
-	MimeType           string                 `json:"mimeType"`
-	RequestHeaders     map[string]string      `json:"requestHeaders"`
-	RequestHeadersText *string                `json:"requestHeadersText"`
-	ConnectionReused   bool                   `json:"connectionReused"`
-	ConnectionID       int                    `json:"connectionId"`
-	RemoteIPAddress    *net.IP                `json:"remoteIPAddress"`
-	RemotePort         *int                   `json:"remotePort"`
-	FromDiskCache      *bool                  `json:"fromDiskCache"`
-	FromServiceWorker  *bool                  `json:"fromServiceWorker"`
-	EncodedDataLength  int                    `json:"encodedDataLength"`
-	Timing             *Timing                `json:"timing"`
-	Protocol           *string                `json:"protocol"`
-	SecurityState      string                 `json:"securityState"`
-	SecurityDetails    map[string]interface{} `json:"securityDetails"`
+	URL                string            `json:"url"`
+	Status             int               `json:"status"`
+	StatusText         string            `json:"statusText"`
+	Headers            map[string]string `json:"headers"`
+	HeadersText        *string           `json:"headersText"`
+	MimeType           string            `json:"mimeType"`
+	RequestHeaders     map[string]string `json:"requestHeaders"`
+	RequestHeadersText *string           `json:"requestHeadersText"`
+	ConnectionReused   bool              `json:"connectionReused"`
+	ConnectionID       int               `json:"connectionId"`
+	RemoteIPAddress    *net.IP           `json:"remoteIPAddress"`
+	RemotePort         *int              `json:"remotePort"`
+	FromDiskCache      *bool             `json:"fromDiskCache"`
+	FromServiceWorker  *bool             `json:"fromServiceWorker"`
+	FromPrefetchCache  *bool             `json:"fromPrefetchCache"`
+	EncodedDataLength  int               `json:"encodedDataLength"`
+	Timing             *Timing           `json:"timing"`
+	Protocol           *string           `json:"protocol"`
+	SecurityState      string            `json:"securityState"`
+	SecurityDetails    *SecurityDetails  `json:"securityDetails"`
+}
+
+type SecurityDetails struct {
+	Protocol                          string   `json:"protocol"`
+	KeyExchange                       string   `json:"keyExchange"`
+	KeyExchangeGroup                  *string  `json:"keyExchangeGroup,omitempty"`
+	Cipher                            string   `json:"cipher"`
+	Mac                               *string  `json:"mac,omitempty"`
+	CertificateID                     int      `json:"certificateId"`
+	SubjectName                       string   `json:"subjectName"`
+	SanList                           []string `json:"sanList"`
+	Issuer                            string   `json:"issuer"`
+	ValidFrom                         float64  `json:"validFrom"`
+	ValidTo                           float64  `json:"validTo"`
+	CertificateTransparencyCompliance string   `json:"certificateTransparencyCompliance"`
 }
 
 type Timing struct {
@@ -97,5 +131,101 @@ type Timing struct {
 	SSLEnd            float64 `json:"sslEnd"`
 	SendStart         float64 `json:"sendStart"`
 	SendEnd           float64 `json:"sendEnd"`
+	PushStart         float64 `json:"pushStart"`
+	PushEnd           float64 `json:"pushEnd"`
 	ReceiveHeadersEnd float64 `json:"receiveHeadersEnd"`
 }
+
+type TargetAttachedToTarget struct {
+	SessionID  string     `json:"sessionId"`
+	TargetInfo TargetInfo `json:"targetInfo"`
+}
+
+type TargetInfo struct {
+	TargetID string `json:"targetId"`
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+}
+
+type TracingDataCollected struct {
+	Value []json.RawMessage `json:"value"`
+}
+
+type TraceEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat"`
+	Ph   string         `json:"ph"`
+	Ts   float64        `json:"ts"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	Args TraceEventArgs `json:"args"`
+}
+
+type TraceEventArgs struct {
+	Frame string          `json:"frame"`
+	Data  json.RawMessage `json:"data"`
+}
+
+type TraceResourceSendRequest struct {
+	RequestID     string `json:"requestId"`
+	Frame         string `json:"frame"`
+	URL           string `json:"url"`
+	RequestMethod string `json:"requestMethod"`
+	Priority      string `json:"priority"`
+	ResourceType  string `json:"resourceType"`
+}
+
+type TraceResourceReceiveResponse struct {
+	RequestID         string        `json:"requestId"`
+	Frame             string        `json:"frame"`
+	StatusCode        int           `json:"statusCode"`
+	MimeType          string        `json:"mimeType"`
+	EncodedDataLength int           `json:"encodedDataLength"`
+	FromCache         bool          `json:"fromCache"`
+	FromServiceWorker bool          `json:"fromServiceWorker"`
+	Timing            *Timing       `json:"timing"`
+	ResponseTime      float64       `json:"responseTime"`
+	Headers           []TraceHeader `json:"headers"`
+	Protocol          *string       `json:"protocol"`
+	ConnectionID      int           `json:"connectionId"`
+	ConnectionReused  bool          `json:"connectionReused"`
+}
+
+type TraceHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type TraceResourceReceivedData struct {
+	RequestID         string `json:"requestId"`
+	Frame             string `json:"frame"`
+	EncodedDataLength int    `json:"encodedDataLength"`
+}
+
+type TraceResourceFinish struct {
+	RequestID         string  `json:"requestId"`
+	DidFail           bool    `json:"didFail"`
+	EncodedDataLength int     `json:"encodedDataLength"`
+	DecodedBodyLength int     `json:"decodedBodyLength"`
+	FinishTime        float64 `json:"finishTime"`
+}
+
+type TraceFrameData struct {
+	Frame              string       `json:"frame"`
+	Parent             string       `json:"parent"`
+	Frames             []TraceFrame `json:"frames"`
+	IsMainFrame        *bool        `json:"isMainFrame"`
+	IsLoadingMainFrame bool         `json:"isLoadingMainFrame"`
+	DocumentLoaderURL  string       `json:"documentLoaderURL"`
+}
+
+type TraceFrame struct {
+	Frame  string `json:"frame"`
+	Parent string `json:"parent"`
+}
+
+type TracePaint struct {
+	Name      string  `json:"name"`
+	Timestamp float64 `json:"timestamp"`
+}
@@ -1,8 +1,30 @@
 package chromedriver2har
 
+import (
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+)
+
 func safeStringDereference(val *string) string {
 	if val == nil {
 		return ""
 	}
 	return *val
 }
+
+func harTime(wallTime float64) har.Time {
+	wallTimeNanoseconds := wallTime * float64(time.Second) / float64(time.Nanosecond)
+	return har.Time{Time: time.Unix(0, int64(wallTimeNanoseconds)).UTC()}
+}
+
+func safeBoolDereference(val *bool) bool {
+	if val == nil {
+		return false
+	}
+	return *val
+}
+
+func durationMilliseconds(milliseconds float64) time.Duration {
+	return time.Duration(milliseconds * float64(time.Millisecond))
+}
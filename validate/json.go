@@ -0,0 +1,100 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/pkg/errors"
+)
+
+type rawHAR struct {
+	Log struct {
+		Pages []struct {
+			StartedDateTime *string `json:"startedDateTime"`
+		} `json:"pages"`
+		Entries []struct {
+			StartedDateTime *string `json:"startedDateTime"`
+			Request         struct {
+				Cookies []rawCookie `json:"cookies"`
+			} `json:"request"`
+			Response struct {
+				Cookies []rawCookie `json:"cookies"`
+			} `json:"response"`
+			Cache struct {
+				BeforeRequest *rawCacheRequest `json:"beforeRequest"`
+				AfterRequest  *rawCacheRequest `json:"afterRequest"`
+			} `json:"cache"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+type rawCookie struct {
+	Expires *string `json:"expires"`
+}
+
+type rawCacheRequest struct {
+	Expires    *string `json:"expires"`
+	LastAccess *string `json:"lastAccess"`
+}
+
+func ValidateJSON(data []byte) ([]Finding, error) {
+	var raw rawHAR
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal har")
+	}
+
+	v := &validator{findings: make([]Finding, 0)}
+	v.rawDates(raw)
+	if HasErrors(v.findings) {
+		return v.findings, nil
+	}
+
+	var h har.HAR
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal har")
+	}
+
+	return Validate(&h), nil
+}
+
+func (v *validator) rawDates(raw rawHAR) {
+	for i, page := range raw.Log.Pages {
+		v.rawDate(fmt.Sprintf("log.pages[%d].startedDateTime", i), page.StartedDateTime, true)
+	}
+
+	for i, entry := range raw.Log.Entries {
+		path := fmt.Sprintf("log.entries[%d]", i)
+		v.rawDate(path+".startedDateTime", entry.StartedDateTime, true)
+		for j, cookie := range entry.Request.Cookies {
+			v.rawDate(fmt.Sprintf("%s.request.cookies[%d].expires", path, j), cookie.Expires, false)
+		}
+		for j, cookie := range entry.Response.Cookies {
+			v.rawDate(fmt.Sprintf("%s.response.cookies[%d].expires", path, j), cookie.Expires, false)
+		}
+		v.rawCacheRequest(path+".cache.beforeRequest", entry.Cache.BeforeRequest)
+		v.rawCacheRequest(path+".cache.afterRequest", entry.Cache.AfterRequest)
+	}
+}
+
+func (v *validator) rawCacheRequest(path string, cacheRequest *rawCacheRequest) {
+	if cacheRequest == nil {
+		return
+	}
+	v.rawDate(path+".expires", cacheRequest.Expires, false)
+	v.rawDate(path+".lastAccess", cacheRequest.LastAccess, true)
+}
+
+func (v *validator) rawDate(path string, date *string, required bool) {
+	if date == nil {
+		if required {
+			v.errorf(path, "missing ISO 8601 date")
+		}
+		return
+	}
+
+	if _, err := time.Parse(time.RFC3339Nano, *date); err != nil {
+		v.errorf(path, "invalid ISO 8601 date %q", *date)
+	}
+}
@@ -0,0 +1,238 @@
+package validate
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+)
+
+const (
+	harVersion      = "1.2"
+	timingTolerance = 1.0
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Path     string   `json:"path"`
+	Message  string   `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.Path, f.Message)
+}
+
+func HasErrors(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+type validator struct {
+	findings []Finding
+}
+
+func (v *validator) errorf(path, format string, args ...interface{}) {
+	v.findings = append(v.findings, Finding{Severity: SeverityError, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) warnf(path, format string, args ...interface{}) {
+	v.findings = append(v.findings, Finding{Severity: SeverityWarning, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func Validate(h *har.HAR) []Finding {
+	v := &validator{findings: make([]Finding, 0)}
+	v.log("log", h.Log)
+	return v.findings
+}
+
+func (v *validator) log(path string, log har.Log) {
+	if log.Version == "" {
+		v.warnf(path+".version", "missing version, defaults to 1.1")
+	} else if log.Version != harVersion {
+		v.warnf(path+".version", "unexpected version %q", log.Version)
+	}
+
+	v.creator(path+".creator", log.Creator)
+
+	pageIDs := make(map[string]bool, len(log.Pages))
+	for i, page := range log.Pages {
+		pagePath := fmt.Sprintf("%s.pages[%d]", path, i)
+		if pageIDs[page.ID] {
+			v.errorf(pagePath+".id", "duplicate page id %q", page.ID)
+		}
+		pageIDs[page.ID] = true
+		v.page(pagePath, page)
+	}
+
+	if log.Entries == nil {
+		v.errorf(path+".entries", "missing entries")
+	}
+	for i, entry := range log.Entries {
+		v.entry(fmt.Sprintf("%s.entries[%d]", path, i), entry, pageIDs)
+	}
+}
+
+func (v *validator) creator(path string, creator har.Creator) {
+	if creator.Name == "" {
+		v.errorf(path+".name", "missing name")
+	}
+	if creator.Version == "" {
+		v.errorf(path+".version", "missing version")
+	}
+}
+
+func (v *validator) page(path string, page har.Page) {
+	if page.ID == "" {
+		v.errorf(path+".id", "missing id")
+	}
+	v.time(path+".startedDateTime", page.StartedDateTime)
+	v.optionalTiming(path+".pageTimings.onContentLoad", page.PageTimings.OnContentLoad)
+	v.optionalTiming(path+".pageTimings.onLoad", page.PageTimings.OnLoad)
+}
+
+func (v *validator) entry(path string, entry har.Entry, pageIDs map[string]bool) {
+	if entry.PageRef != nil && !pageIDs[*entry.PageRef] {
+		v.errorf(path+".pageref", "references unknown page %q", *entry.PageRef)
+	}
+	v.time(path+".startedDateTime", entry.StartedDateTime)
+	if entry.Time < 0 {
+		v.errorf(path+".time", "negative time %v", entry.Time)
+	}
+
+	v.request(path+".request", entry.Request)
+	v.response(path+".response", entry.Response)
+	v.cache(path+".cache", entry.Cache)
+	v.timings(path+".timings", entry.Timings, entry.Time)
+}
+
+func (v *validator) request(path string, request har.Request) {
+	if request.Method == "" {
+		v.errorf(path+".method", "missing method")
+	}
+	if request.URL.String() == "" {
+		v.errorf(path+".url", "missing url")
+	} else if !request.URL.IsAbs() {
+		v.errorf(path+".url", "url %q is not absolute", request.URL.String())
+	}
+	if request.HTTPVersion == "" {
+		v.warnf(path+".httpVersion", "missing http version")
+	}
+	if request.Cookies == nil {
+		v.errorf(path+".cookies", "missing cookies")
+	}
+	if request.Headers == nil {
+		v.errorf(path+".headers", "missing headers")
+	}
+	if request.QueryString == nil {
+		v.errorf(path+".queryString", "missing query string")
+	}
+	v.size(path+".headersSize", request.HeadersSize)
+	v.size(path+".bodySize", request.BodySize)
+}
+
+func (v *validator) response(path string, response har.Response) {
+	if response.Status < 0 {
+		v.errorf(path+".status", "negative status %d", response.Status)
+	}
+	if response.HTTPVersion == "" {
+		v.warnf(path+".httpVersion", "missing http version")
+	}
+	if response.Cookies == nil {
+		v.errorf(path+".cookies", "missing cookies")
+	}
+	if response.Headers == nil {
+		v.errorf(path+".headers", "missing headers")
+	}
+	if response.Content.Size < 0 {
+		v.errorf(path+".content.size", "negative size %d", response.Content.Size)
+	}
+	if response.Content.MIMEType == "" {
+		v.warnf(path+".content.mimeType", "missing mime type")
+	}
+	v.size(path+".headersSize", response.HeadersSize)
+	v.size(path+".bodySize", response.BodySize)
+}
+
+func (v *validator) cache(path string, cache har.Cache) {
+	v.cacheRequest(path+".beforeRequest", cache.BeforeRequest)
+	v.cacheRequest(path+".afterRequest", cache.AfterRequest)
+}
+
+func (v *validator) cacheRequest(path string, cacheRequest *har.CacheRequest) {
+	if cacheRequest == nil {
+		return
+	}
+	v.time(path+".lastAccess", cacheRequest.LastAccess)
+	if cacheRequest.HitCount < 0 {
+		v.errorf(path+".hitCount", "negative hit count %d", cacheRequest.HitCount)
+	}
+}
+
+func (v *validator) timings(path string, timings har.Timings, entryTime float64) {
+	sum := 0.0
+	for _, timing := range []struct {
+		name  string
+		value *float64
+	}{
+		{"blocked", timings.Blocked},
+		{"dns", timings.DNS},
+		{"connect", timings.Connect},
+		{"ssl", timings.SSL},
+	} {
+		v.optionalTiming(path+"."+timing.name, timing.value)
+		if timing.value != nil && *timing.value > 0 && timing.name != "ssl" {
+			sum += *timing.value
+		}
+	}
+
+	for _, timing := range []struct {
+		name  string
+		value float64
+	}{
+		{"send", timings.Send},
+		{"wait", timings.Wait},
+		{"receive", timings.Receive},
+	} {
+		if timing.value < 0 {
+			v.errorf(path+"."+timing.name, "negative timing %v", timing.value)
+		}
+		sum += math.Max(timing.value, 0)
+	}
+
+	if timings.SSL != nil && *timings.SSL > 0 && (timings.Connect == nil || *timings.SSL > *timings.Connect) {
+		v.errorf(path+".ssl", "ssl timing %v exceeds connect timing", *timings.SSL)
+	}
+
+	if math.Abs(sum-entryTime) > timingTolerance {
+		v.errorf(path, "timings sum to %v but entry time is %v", sum, entryTime)
+	}
+}
+
+func (v *validator) optionalTiming(path string, timing *float64) {
+	if timing != nil && *timing < 0 && *timing != -1 {
+		v.errorf(path, "timing %v must be non-negative or -1", *timing)
+	}
+}
+
+func (v *validator) size(path string, size int) {
+	if size < -1 {
+		v.errorf(path, "size %d must be non-negative or -1", size)
+	}
+}
+
+func (v *validator) time(path string, t har.Time) {
+	if t.IsZero() {
+		v.errorf(path, "missing ISO 8601 date")
+	}
+}
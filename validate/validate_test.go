@@ -0,0 +1,83 @@
+package validate
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/stretchr/testify/require"
+)
+
+func validHAR() *har.HAR {
+	pageRef := "page_1"
+	onLoad := 250.0
+	unavailable := -1.0
+	u, _ := url.Parse("https://example.com/")
+	started := har.Time{Time: time.Date(2026, 10, 17, 10, 0, 0, 123000000, time.UTC)}
+
+	return &har.HAR{
+		Log: har.Log{
+			Version: "1.2",
+			Creator: har.Creator{Name: "test", Version: "1"},
+			Pages: []har.Page{{
+				StartedDateTime: started,
+				ID:              pageRef,
+				Title:           "Example",
+				PageTimings:     har.PageTimings{OnLoad: &onLoad},
+			}},
+			Entries: []har.Entry{{
+				PageRef:         &pageRef,
+				StartedDateTime: started,
+				Time:            30,
+				Request: har.Request{
+					Method:      "GET",
+					URL:         har.URL{URL: *u},
+					HTTPVersion: "HTTP/1.1",
+					Cookies:     []har.Cookie{},
+					Headers:     []har.Header{},
+					QueryString: []har.QueryStringParam{},
+					HeadersSize: 40,
+					BodySize:    -1,
+				},
+				Response: har.Response{
+					Status:      200,
+					HTTPVersion: "HTTP/1.1",
+					Cookies:     []har.Cookie{},
+					Headers:     []har.Header{},
+					Content:     har.Content{Size: 10, MIMEType: "text/html"},
+					HeadersSize: 50,
+					BodySize:    10,
+				},
+				Timings: har.Timings{Blocked: &unavailable, Send: 10, Wait: 15, Receive: 5},
+			}},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	require.Empty(t, Validate(validHAR()))
+
+	h := validHAR()
+	missingPage := "page_2"
+	h.Log.Entries[0].PageRef = &missingPage
+	h.Log.Entries[0].Response.BodySize = -5
+	h.Log.Entries[0].Timings.Wait = 100
+
+	findings := Validate(h)
+	require.True(t, HasErrors(findings))
+
+	paths := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		paths = append(paths, finding.Path)
+	}
+	require.Contains(t, paths, "log.entries[0].pageref")
+	require.Contains(t, paths, "log.entries[0].response.bodySize")
+	require.Contains(t, paths, "log.entries[0].timings")
+}
+
+func TestValidateJSON(t *testing.T) {
+	findings, err := ValidateJSON([]byte(`{"log":{"version":"1.2","creator":{"name":"test","version":"1"},"pages":[{"id":"page_1","startedDateTime":"yesterday"}],"entries":[]}}`))
+	require.NoError(t, err)
+	require.Equal(t, []Finding{{Severity: SeverityError, Path: "log.pages[0].startedDateTime", Message: `invalid ISO 8601 date "yesterday"`}}, findings)
+}
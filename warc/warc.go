@@ -0,0 +1,236 @@
+package warc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/export"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/pkg/errors"
+)
+
+const (
+	Version = "WARC/1.1"
+
+	TypeInfo     = "warcinfo"
+	TypeRequest  = "request"
+	TypeResponse = "response"
+
+	contentTypeFields   = "application/warc-fields"
+	contentTypeRequest  = "application/http;msgtype=request"
+	contentTypeResponse = "application/http;msgtype=response"
+)
+
+type Options struct {
+	// Bodies looks up response bodies by the entries' DevTools request IDs,
+	// e.g. from a cdp.Capture. Entries it has no body for fall back to the
+	// content text recorded in the HAR.
+	Bodies chromedriver2har.BodyProvider
+	Gzip   bool
+}
+
+type record struct {
+	headers []field
+	block   []byte
+}
+
+type field struct {
+	name  string
+	value string
+}
+
+// Write converts the HTTP entries of a HAR into WARC request and response
+// records, preceded by a warcinfo record.
+func Write(w io.Writer, h *har.HAR, opts Options) error {
+	info := infoRecord(h)
+	if err := writeRecord(w, info, opts.Gzip); err != nil {
+		return errors.Wrap(err, "failed to write warcinfo record")
+	}
+
+	for i, entry := range h.Log.Entries {
+		scheme := entry.Request.URL.Scheme
+		if scheme != "http" && scheme != "https" {
+			continue
+		}
+
+		records, err := entryRecords(i, entry, opts)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create records for %q", entry.Request.URL.String())
+		}
+
+		for _, r := range records {
+			r.headers = append(r.headers, field{"WARC-Warcinfo-ID", info.id()})
+			if err := writeRecord(w, r, opts.Gzip); err != nil {
+				return errors.Wrapf(err, "failed to write records for %q", entry.Request.URL.String())
+			}
+		}
+	}
+	return nil
+}
+
+// FromEventSource converts DevTools events into WARC records, with response
+// bodies from opts.Bodies.
+func FromEventSource(w io.Writer, source chromedriver2har.EventSource, opts Options) error {
+	var harOpts []chromedriver2har.Option
+	if opts.Bodies != nil {
+		harOpts = append(harOpts, chromedriver2har.WithBodyProvider(opts.Bodies))
+	}
+
+	h, err := chromedriver2har.FromEventSource(source, harOpts...)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert events")
+	}
+	return Write(w, h, opts)
+}
+
+func responseBody(entry har.Entry, opts Options) ([]byte, error) {
+	if opts.Bodies != nil {
+		body, ok := opts.Bodies.ResponseBody(entry.Custom.String(chromedriver2har.CustomRequestID))
+		if ok && body.Base64Encoded {
+			return base64.StdEncoding.DecodeString(body.Body)
+		}
+		if ok {
+			return []byte(body.Body), nil
+		}
+	}
+	return chromedriver2har.ContentBody(entry.Response.Content)
+}
+
+func infoRecord(h *har.HAR) record {
+	var block bytes.Buffer
+	fmt.Fprintf(&block, "software: %s/%s\r\n", h.Log.Creator.Name, h.Log.Creator.Version)
+	fmt.Fprintf(&block, "format: WARC File Format 1.1\r\n")
+	fmt.Fprintf(&block, "conformsTo: http://iipc.github.io/warc-specifications/specifications/warc-format/warc-1.1/\r\n")
+
+	date := time.Now()
+	if len(h.Log.Entries) > 0 {
+		date = h.Log.Entries[0].StartedDateTime.Time
+	}
+
+	return newRecord(TypeInfo, recordID(TypeInfo, -1, "", date), date, contentTypeFields, block.Bytes())
+}
+
+// entryRecords returns the response record followed by the request record,
+// which points back at it through WARC-Concurrent-To. Failed requests have no
+// response record.
+func entryRecords(index int, entry har.Entry, opts Options) ([]record, error) {
+	targetURI := entry.Request.URL.String()
+	date := entry.StartedDateTime.Time
+
+	request := newRecord(TypeRequest, recordID(TypeRequest, index, targetURI, date), date, contentTypeRequest, []byte(export.HTTP(entry, export.Options{})))
+	request.headers = append(request.headers, field{"WARC-Target-URI", targetURI})
+
+	if entry.Response.Status == 0 {
+		return []record{request}, nil
+	}
+
+	body, err := responseBody(entry, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get response body")
+	}
+
+	response := newRecord(TypeResponse, recordID(TypeResponse, index, targetURI, date), date, contentTypeResponse, responseBlock(entry.Response, body))
+	response.headers = append(response.headers,
+		field{"WARC-Target-URI", targetURI},
+		field{"WARC-Payload-Digest", digest(body)},
+	)
+	if entry.ServerIPAddress != nil {
+		response.headers = append(response.headers, field{"WARC-IP-Address", entry.ServerIPAddress.String()})
+	}
+	if body == nil && entry.Response.Content.Size > 0 {
+		response.headers = append(response.headers, field{"WARC-Truncated", "unspecified"})
+	}
+
+	request.headers = append(request.headers, field{"WARC-Concurrent-To", response.id()})
+	return []record{response, request}, nil
+}
+
+// responseBlock writes the response as HTTP/1.1, since WARC readers expect an
+// HTTP/1 message regardless of the protocol the response arrived over.
+func responseBlock(response har.Response, body []byte) []byte {
+	statusText := response.StatusText
+	if statusText == "" {
+		// HTTP/2 responses have no reason phrase.
+		statusText = http.StatusText(response.Status)
+	}
+
+	// The payload is stored decoded, so the recorded transfer headers no
+	// longer apply.
+	header := chromedriver2har.ResponseHeader(response)
+	header.Set("Content-Length", fmt.Sprint(len(body)))
+
+	var block bytes.Buffer
+	fmt.Fprintf(&block, "HTTP/1.1 %d %s\r\n", response.Status, statusText)
+	header.Write(&block)
+	block.WriteString("\r\n")
+	block.Write(body)
+	return block.Bytes()
+}
+
+func newRecord(recordType, id string, date time.Time, contentType string, block []byte) record {
+	return record{
+		headers: []field{
+			{"WARC-Type", recordType},
+			{"WARC-Record-ID", id},
+			{"WARC-Date", date.UTC().Format(time.RFC3339Nano)},
+			{"Content-Type", contentType},
+			{"WARC-Block-Digest", digest(block)},
+		},
+		block: block,
+	}
+}
+
+func (r record) id() string {
+	for _, f := range r.headers {
+		if f.name == "WARC-Record-ID" {
+			return f.value
+		}
+	}
+	return ""
+}
+
+// recordID derives a stable UUID from the record's position and target so
+// converting the same capture twice produces identical files.
+func recordID(recordType string, index int, targetURI string, date time.Time) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s\x00%d\x00%s\x00%d", recordType, index, targetURI, date.UnixNano())))
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("<urn:uuid:%x-%x-%x-%x-%x>", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+func digest(data []byte) string {
+	sum := sha1.Sum(data)
+	return "sha1:" + base32.StdEncoding.EncodeToString(sum[:])
+}
+
+// writeRecord writes a single record, as its own gzip member when compressing
+// so readers can seek to individual records.
+func writeRecord(w io.Writer, r record, compress bool) error {
+	if compress {
+		gz := gzip.NewWriter(w)
+		if err := writeRecord(gz, r, false); err != nil {
+			return err
+		}
+		return gz.Close()
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(Version + "\r\n")
+	for _, f := range r.headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", f.name, f.value)
+	}
+	fmt.Fprintf(&buf, "Content-Length: %d\r\n\r\n", len(r.block))
+	buf.Write(r.block)
+	buf.WriteString("\r\n\r\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
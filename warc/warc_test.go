@@ -0,0 +1,160 @@
+package warc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har"
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/jordanpotter/chromedriver2har/internal/hartest"
+	"github.com/stretchr/testify/require"
+)
+
+type warcRecord struct {
+	header http.Header
+	block  string
+}
+
+func readRecords(t *testing.T, data []byte) []warcRecord {
+	var records []warcRecord
+	reader := bufio.NewReader(bytes.NewReader(data))
+	for {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF {
+			return records
+		}
+		require.NoError(t, err)
+		require.Equal(t, Version+"\r\n", line)
+
+		header, err := textproto.NewReader(reader).ReadMIMEHeader()
+		require.NoError(t, err)
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		require.NoError(t, err)
+
+		block := make([]byte, length+len("\r\n\r\n"))
+		_, err = io.ReadFull(reader, block)
+		require.NoError(t, err)
+		require.Equal(t, "\r\n\r\n", string(block[length:]))
+
+		records = append(records, warcRecord{header: http.Header(header), block: string(block[:length])})
+	}
+}
+
+func entry(method, rawURL string) *hartest.Entry {
+	return hartest.NewEntry(method, rawURL).
+		Started(time.Date(2024, 1, 2, 3, 4, 5, 250000000, time.UTC)).
+		ResponseHeader(":status", "200").
+		ResponseHeader("content-encoding", "gzip").
+		ResponseHeader("set-cookie", "a=1\nb=2")
+}
+
+func TestWrite(t *testing.T) {
+	ip := net.ParseIP("192.0.2.1")
+	h := &har.HAR{Log: har.Log{Creator: har.Creator{Name: "chromedriver2har", Version: "0.1"}, Entries: []har.Entry{
+		entry("GET", "https://example.com/index.html").Status(200).StatusText("OK").ContentSize(13).Body("<html></html>").Build(),
+		entry("GET", "data:text/plain,hi").Status(200).StatusText("OK").ContentSize(2).Body("hi").Build(),
+		entry("POST", "https://example.com/api").PostData("", "x=1").Body("").Build(),
+	}}}
+	h.Log.Entries[0].ServerIPAddress = &ip
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, h, Options{}))
+
+	records := readRecords(t, buf.Bytes())
+	require.Len(t, records, 4)
+
+	info, response, request, failed := records[0], records[1], records[2], records[3]
+	require.Equal(t, TypeInfo, info.header.Get("WARC-Type"))
+	require.Contains(t, info.block, "software: chromedriver2har/0.1\r\n")
+
+	require.Equal(t, TypeResponse, response.header.Get("WARC-Type"))
+	require.Equal(t, "https://example.com/index.html", response.header.Get("WARC-Target-URI"))
+	require.Equal(t, "2024-01-02T03:04:05.25Z", response.header.Get("WARC-Date"))
+	require.Equal(t, "192.0.2.1", response.header.Get("WARC-IP-Address"))
+	require.Equal(t, info.header.Get("WARC-Record-ID"), response.header.Get("WARC-Warcinfo-ID"))
+	require.Equal(t, digest([]byte("<html></html>")), response.header.Get("WARC-Payload-Digest"))
+	require.Equal(t, digest([]byte(response.block)), response.header.Get("WARC-Block-Digest"))
+
+	httpResponse, err := http.ReadResponse(bufio.NewReader(strings.NewReader(response.block)), nil)
+	require.NoError(t, err)
+	require.Equal(t, 200, httpResponse.StatusCode)
+	require.Empty(t, httpResponse.Header.Get("Content-Encoding"))
+	require.Equal(t, []string{"a=1", "b=2"}, httpResponse.Header["Set-Cookie"])
+	body, err := ioutil.ReadAll(httpResponse.Body)
+	require.NoError(t, err)
+	require.Equal(t, "<html></html>", string(body))
+
+	require.Equal(t, TypeRequest, request.header.Get("WARC-Type"))
+	require.Equal(t, response.header.Get("WARC-Record-ID"), request.header.Get("WARC-Concurrent-To"))
+	require.Equal(t, "GET /index.html HTTP/1.1\r\nHost: example.com\r\n\r\n", request.block)
+
+	require.Equal(t, TypeRequest, failed.header.Get("WARC-Type"))
+	require.Empty(t, failed.header.Get("WARC-Concurrent-To"))
+	require.True(t, strings.HasSuffix(failed.block, "\r\n\r\nx=1"))
+
+	var again bytes.Buffer
+	require.NoError(t, Write(&again, h, Options{}))
+	require.Equal(t, buf.String(), again.String())
+}
+
+type bodies map[string]chromedriver2har.NetworkGetResponseBody
+
+func (b bodies) ResponseBody(requestID string) (chromedriver2har.NetworkGetResponseBody, bool) {
+	body, ok := b[requestID]
+	return body, ok
+}
+
+func TestWriteBodiesAndGzip(t *testing.T) {
+	h := &har.HAR{Log: har.Log{Entries: []har.Entry{
+		entry("GET", "https://example.com/").Custom(chromedriver2har.CustomRequestID, "1.1").Status(200).StatusText("OK").ContentSize(10).Build(),
+	}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, h, Options{}))
+	require.Equal(t, "unspecified", readRecords(t, buf.Bytes())[1].header.Get("WARC-Truncated"))
+
+	buf.Reset()
+	provider := bodies{"1.1": {Body: "cHJvdmlkZWQ=", Base64Encoded: true}}
+	require.NoError(t, Write(&buf, h, Options{Bodies: provider, Gzip: true}))
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(gz)
+	require.NoError(t, err)
+
+	records := readRecords(t, data)
+	require.Len(t, records, 3)
+	require.Empty(t, records[1].header.Get("WARC-Truncated"))
+	require.True(t, strings.HasSuffix(records[1].block, "\r\n\r\nprovided"))
+}
+
+func TestFromEventSource(t *testing.T) {
+	event := func(method, params string) chromedriver2har.Event {
+		return chromedriver2har.Event{Method: method, Params: json.RawMessage(params), TargetID: "ABC"}
+	}
+	events := []chromedriver2har.Event{
+		event(chromedriver2har.MethodNetworkRequestWillBeSent, `{"requestId":"1.1","loaderId":"1.1","documentURL":"https://example.com/","request":{"url":"https://example.com/","method":"GET","headers":{}},"timestamp":100.0,"wallTime":1760695200.123}`),
+		event(chromedriver2har.MethodNetworkResponseReceived, `{"requestId":"1.1","loaderId":"1.1","timestamp":100.05,"type":"Document","response":{"url":"https://example.com/","status":200,"statusText":"OK","headers":{"Content-Type":"text/html"},"mimeType":"text/html","protocol":"http/1.1"}}`),
+		event(chromedriver2har.MethodNetworkLoadingFinished, `{"requestId":"1.1","timestamp":100.07,"encodedDataLength":600}`),
+	}
+
+	var buf bytes.Buffer
+	source := chromedriver2har.NewSliceSource(events)
+	require.NoError(t, FromEventSource(&buf, source, Options{Bodies: bodies{"1.1": {Body: "<html></html>"}}}))
+
+	records := readRecords(t, buf.Bytes())
+	require.Len(t, records, 3)
+	require.Equal(t, "https://example.com/", records[1].header.Get("WARC-Target-URI"))
+	require.True(t, strings.HasSuffix(records[1].block, "\r\n\r\n<html></html>"))
+}
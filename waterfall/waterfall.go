@@ -0,0 +1,273 @@
+package waterfall
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultWidth      = 1200
+	defaultLabelWidth = 360
+	defaultRowHeight  = 18
+
+	headerHeight = 24
+	barPadding   = 3
+	labelLength  = 60
+	targetTicks  = 10
+)
+
+type Phase struct {
+	Name  string
+	Color string
+}
+
+var Phases = []Phase{
+	{"blocked", "#c4c4c4"},
+	{"dns", "#1f7c83"},
+	{"connect", "#e58226"},
+	{"ssl", "#c141cd"},
+	{"send", "#8e8e8e"},
+	{"wait", "#00a846"},
+	{"receive", "#2f7bdb"},
+}
+
+const (
+	onContentLoadColor = "#1a1aa6"
+	onLoadColor        = "#c80000"
+)
+
+type Options struct {
+	Width      int
+	LabelWidth int
+	RowHeight  int
+}
+
+type chart struct {
+	opts    Options
+	origin  time.Time
+	span    float64
+	entries []har.Entry
+	pages   []har.Page
+}
+
+func newChart(h *har.HAR, opts Options) (*chart, error) {
+	if opts.Width <= 0 {
+		opts.Width = defaultWidth
+	}
+	if opts.LabelWidth <= 0 {
+		opts.LabelWidth = defaultLabelWidth
+	}
+	if opts.RowHeight <= 0 {
+		opts.RowHeight = defaultRowHeight
+	}
+	if opts.Width <= opts.LabelWidth {
+		return nil, errors.Errorf("width %d leaves no room for bars after label width %d", opts.Width, opts.LabelWidth)
+	}
+
+	c := &chart{
+		opts:    opts,
+		entries: har.SortedByStart(h.Log.Entries),
+		pages:   h.Log.Pages,
+	}
+
+	for i, entry := range c.entries {
+		if i == 0 || entry.StartedDateTime.Before(c.origin) {
+			c.origin = entry.StartedDateTime.Time
+		}
+	}
+	for _, page := range c.pages {
+		if c.origin.IsZero() || page.StartedDateTime.Before(c.origin) {
+			c.origin = page.StartedDateTime.Time
+		}
+	}
+
+	for _, entry := range c.entries {
+		c.span = math.Max(c.span, c.offset(entry.StartedDateTime)+entry.Time)
+	}
+	for _, page := range c.pages {
+		for _, timing := range []*float64{page.PageTimings.OnContentLoad, page.PageTimings.OnLoad} {
+			if timing != nil && *timing >= 0 {
+				c.span = math.Max(c.span, c.offset(page.StartedDateTime)+*timing)
+			}
+		}
+	}
+	if c.span <= 0 {
+		c.span = 1
+	}
+
+	return c, nil
+}
+
+func (c *chart) offset(t har.Time) float64 {
+	return float64(t.Sub(c.origin)) / float64(time.Millisecond)
+}
+
+func (c *chart) x(ms float64) float64 {
+	return float64(c.opts.LabelWidth) + ms*float64(c.opts.Width-c.opts.LabelWidth)/c.span
+}
+
+func (c *chart) height() int {
+	return headerHeight + len(c.entries)*c.opts.RowHeight
+}
+
+func SVG(w io.Writer, h *har.HAR, opts Options) error {
+	c, err := newChart(h, opts)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	c.writeSVG(&buf)
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+func HTML(w io.Writer, h *har.HAR, opts Options) error {
+	c, err := newChart(h, opts)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Waterfall</title>\n")
+	buf.WriteString("<style>body{font-family:sans-serif;margin:16px}.legend span{display:inline-block;margin-right:12px}.legend i{display:inline-block;width:10px;height:10px;margin-right:4px}</style>\n")
+	buf.WriteString("</head>\n<body>\n<div class=\"legend\">")
+	for _, phase := range Phases {
+		fmt.Fprintf(&buf, "<span><i style=\"background:%s\"></i>%s</span>", phase.Color, phase.Name)
+	}
+	fmt.Fprintf(&buf, "<span><i style=\"background:%s\"></i>onContentLoad</span>", onContentLoadColor)
+	fmt.Fprintf(&buf, "<span><i style=\"background:%s\"></i>onLoad</span>", onLoadColor)
+	buf.WriteString("</div>\n")
+	c.writeSVG(&buf)
+	buf.WriteString("</body>\n</html>\n")
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+func (c *chart) writeSVG(buf *bytes.Buffer) {
+	width, height := c.opts.Width, c.height()
+	fmt.Fprintf(buf, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"sans-serif\" font-size=\"11\">\n", width, height, width, height)
+	fmt.Fprintf(buf, "<rect width=\"%d\" height=\"%d\" fill=\"#ffffff\"/>\n", width, height)
+
+	step := tickStep(c.span)
+	for ms := 0.0; ms <= c.span; ms += step {
+		x := c.x(ms)
+		fmt.Fprintf(buf, "<line x1=\"%.1f\" y1=\"%d\" x2=\"%.1f\" y2=\"%d\" stroke=\"#e0e0e0\"/>\n", x, headerHeight-4, x, height)
+		fmt.Fprintf(buf, "<text x=\"%.1f\" y=\"%d\" fill=\"#666666\">%s</text>\n", x+2, headerHeight-8, formatMilliseconds(ms))
+	}
+
+	for i, entry := range c.entries {
+		c.writeRow(buf, i, entry)
+	}
+
+	for _, page := range c.pages {
+		c.writeMarker(buf, page, page.PageTimings.OnContentLoad, "onContentLoad", onContentLoadColor)
+		c.writeMarker(buf, page, page.PageTimings.OnLoad, "onLoad", onLoadColor)
+	}
+
+	buf.WriteString("</svg>\n")
+}
+
+func (c *chart) writeRow(buf *bytes.Buffer, i int, entry har.Entry) {
+	y := headerHeight + i*c.opts.RowHeight
+	label := fmt.Sprintf("%s %s", entry.Request.Method, entry.Request.URL.String())
+
+	fmt.Fprintf(buf, "<g>\n<title>%s\n%d %s, %s</title>\n", html.EscapeString(label), entry.Response.Status, html.EscapeString(entry.Response.StatusText), formatMilliseconds(entry.Time))
+	if i%2 == 1 {
+		fmt.Fprintf(buf, "<rect x=\"0\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"#f5f5f5\"/>\n", y, c.opts.Width, c.opts.RowHeight)
+	}
+	fmt.Fprintf(buf, "<text x=\"4\" y=\"%d\" fill=\"%s\">%s</text>\n", y+c.opts.RowHeight-5, labelColor(entry), html.EscapeString(truncate(label, labelLength)))
+
+	start := c.offset(entry.StartedDateTime)
+	for _, phase := range phaseDurations(entry.Timings) {
+		if phase.duration <= 0 {
+			continue
+		}
+		x1, x2 := c.x(start), c.x(start+phase.duration)
+		fmt.Fprintf(buf, "<rect x=\"%.1f\" y=\"%d\" width=\"%.1f\" height=\"%d\" fill=\"%s\"><title>%s: %s</title></rect>\n", x1, y+barPadding, math.Max(x2-x1, 1), c.opts.RowHeight-2*barPadding, phase.color, phase.name, formatMilliseconds(phase.duration))
+		start += phase.duration
+	}
+	buf.WriteString("</g>\n")
+}
+
+func (c *chart) writeMarker(buf *bytes.Buffer, page har.Page, timing *float64, name, color string) {
+	if timing == nil || *timing < 0 {
+		return
+	}
+	x := c.x(c.offset(page.StartedDateTime) + *timing)
+	fmt.Fprintf(buf, "<line x1=\"%.1f\" y1=\"%d\" x2=\"%.1f\" y2=\"%d\" stroke=\"%s\" stroke-width=\"1.5\"><title>%s %s: %s</title></line>\n", x, headerHeight-4, x, c.height(), color, html.EscapeString(page.ID), name, formatMilliseconds(*timing))
+}
+
+type phaseDuration struct {
+	name     string
+	color    string
+	duration float64
+}
+
+// HAR connect times include ssl, so the connect bar only covers the part of
+// the connection that came before the TLS handshake.
+func phaseDurations(timings har.Timings) []phaseDuration {
+	optional := func(v *float64) float64 {
+		if v == nil || *v < 0 {
+			return 0
+		}
+		return *v
+	}
+
+	ssl := optional(timings.SSL)
+	durations := map[string]float64{
+		"blocked": optional(timings.Blocked),
+		"dns":     optional(timings.DNS),
+		"connect": math.Max(optional(timings.Connect)-ssl, 0),
+		"ssl":     ssl,
+		"send":    math.Max(timings.Send, 0),
+		"wait":    math.Max(timings.Wait, 0),
+		"receive": math.Max(timings.Receive, 0),
+	}
+
+	phases := make([]phaseDuration, 0, len(Phases))
+	for _, phase := range Phases {
+		phases = append(phases, phaseDuration{name: phase.Name, color: phase.Color, duration: durations[phase.Name]})
+	}
+	return phases
+}
+
+func tickStep(span float64) float64 {
+	raw := span / targetTicks
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, multiple := range []float64{1, 2, 5} {
+		if multiple*magnitude >= raw {
+			return multiple * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+func formatMilliseconds(ms float64) string {
+	if ms >= 1000 {
+		return fmt.Sprintf("%.2f s", ms/1000)
+	}
+	return fmt.Sprintf("%.0f ms", ms)
+}
+
+func labelColor(entry har.Entry) string {
+	if entry.Response.Status == 0 || entry.Response.Status >= 400 {
+		return "#c80000"
+	}
+	return "#222222"
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
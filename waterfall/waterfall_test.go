@@ -0,0 +1,87 @@
+package waterfall
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jordanpotter/chromedriver2har/har"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSVG(t *testing.T) {
+	start := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	u, err := url.Parse("https://example.com/search?q=a&b=<c>")
+	require.NoError(t, err)
+
+	dns, connect, ssl := 10.0, 40.0, 25.0
+	onContentLoad, onLoad := 300.0, 450.0
+	h := &har.HAR{Log: har.Log{
+		Pages: []har.Page{{ID: "page_1", StartedDateTime: har.Time{Time: start}, PageTimings: har.PageTimings{OnContentLoad: &onContentLoad, OnLoad: &onLoad}}},
+		Entries: []har.Entry{
+			{
+				StartedDateTime: har.Time{Time: start.Add(100 * time.Millisecond)},
+				Time:            50,
+				Request:         har.Request{Method: "GET", URL: har.URL{URL: *u}},
+				Response:        har.Response{Status: 404},
+				Timings:         har.Timings{Send: 1, Wait: 40, Receive: 9},
+			},
+			{
+				StartedDateTime: har.Time{Time: start},
+				Time:            200,
+				Request:         har.Request{Method: "GET", URL: har.URL{URL: *u}},
+				Response:        har.Response{Status: 200},
+				Timings:         har.Timings{DNS: &dns, Connect: &connect, SSL: &ssl, Send: 1, Wait: 120, Receive: 29},
+			},
+		},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, SVG(&buf, h, Options{Width: 1000, LabelWidth: 200}))
+
+	var rects, lines int
+	decoder := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if start, ok := token.(xml.StartElement); ok {
+			switch start.Name.Local {
+			case "rect":
+				rects++
+			case "line":
+				lines++
+			}
+		}
+	}
+
+	// Background, one zebra stripe, six phases for the first entry and three
+	// for the second.
+	require.Equal(t, 1+1+6+3, rects)
+	require.Equal(t, len(ticks(450))+2, lines)
+	require.Contains(t, buf.String(), `<rect x="200.0" y="27" width="17.8" height="12" fill="#1f7c83"><title>dns: 10 ms</title></rect>`)
+	require.Contains(t, buf.String(), `<line x1="1000.0" y1="20" x2="1000.0" y2="60" stroke="#c80000"`)
+
+	buf.Reset()
+	require.NoError(t, HTML(&buf, h, Options{}))
+	require.True(t, strings.HasPrefix(buf.String(), "<!DOCTYPE html>"))
+	require.Contains(t, buf.String(), "onContentLoad</span>")
+
+	require.Error(t, SVG(&buf, h, Options{Width: 200, LabelWidth: 200}))
+	require.Error(t, HTML(&buf, h, Options{Width: 300}))
+}
+
+func ticks(span float64) []float64 {
+	var ticks []float64
+	step := tickStep(span)
+	for ms := 0.0; ms <= span; ms += step {
+		ticks = append(ticks, ms)
+	}
+	return ticks
+}